@@ -2,26 +2,37 @@ package solanarpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
-	"os"
 	"reflect"
 	"strings"
 	"time"
-
-	log "github.com/sirupsen/logrus"
 )
 
+//go:generate go run ./cmd/genapi -spec methods.json -api api_gen.go -parse queryParse_gen.go
+
 const (
-	HttpPort           = 8899
-	WebsocketPort      = 890
+	HttpPort = 8899
+	// WebsocketPort is the default PubSub port of a node, HttpPort + 1. It
+	// read 890 before, where no node listens.
+	WebsocketPort      = 8900
 	DefaultConnTimeOut = 5 * time.Second
 	HTTPContentType    = "application/json"
 	RequestVersion     = "2.0"
 	MAXID              = 10000
 	RequestTimeout     = 5 * time.Second
+	MaxBatchSize       = 100
+	// MaxSignatureStatuses is how many signatures a node accepts in one getSignatureStatuses
+	MaxSignatureStatuses = 256
+	// DefaultMaxResponseSize caps response bodies; full blocks are a few MB
+	DefaultMaxResponseSize = 64 << 20
+	// MaxErrorBodySize is how much of a non-2xx body HTTPError keeps
+	MaxErrorBodySize = 512
 )
 
 type EncodeMethod string
@@ -32,6 +43,7 @@ const (
 	Base58                    EncodeMethod       = "base58"
 	Base64                    EncodeMethod       = "base64"
 	Base64Zstd                EncodeMethod       = "base64+zstd"
+	Json                      EncodeMethod       = "json"
 	JsonParsed                EncodeMethod       = "jsonParsed"
 	EncodeDefault             EncodeMethod       = "jsonParsed"
 	Full                      TransactionDetails = "full"
@@ -61,6 +73,32 @@ func (s SolanaEndpoint) DialAddress() (string, error) {
 type RPCClient struct {
 	Endpoint
 	Client *http.Client
+	// BatchSize caps the number of requests sent in one batch POST; 0 means MaxBatchSize
+	BatchSize int
+	// Retry resends requests failing with a retryable error; nil disables retries
+	Retry *RetryPolicy
+	// RateLimit holds requests back to stay within provider quotas; nil disables it
+	RateLimit *RateLimiter
+	// Middlewares wrap every DoPostRequest, the first being the outermost
+	Middlewares []Middleware
+	// Logger receives the client logs; nil discards them
+	Logger Logger
+	// MaxResponseSize caps response bodies; 0 means DefaultMaxResponseSize
+	MaxResponseSize int64
+	// Timeout bounds each HTTP round trip on top of Client.Timeout; 0 means none
+	Timeout time.Duration
+	// Commitment and Encoding fill the config of requests leaving them empty
+	Commitment CommitmentVal
+	Encoding   EncodeMethod
+	UserAgent  string
+	// Header is added to every request
+	Header http.Header
+	// Metrics observes every HTTP round trip; nil disables it
+	Metrics Metrics
+	// Tracer opens a span around every request and batch; nil disables it
+	Tracer Tracer
+	// Cache answers requests for immutable data without a round trip; nil disables it
+	Cache *ResponseCache
 }
 
 func (r *RPCClient) Init(host string) error {
@@ -71,15 +109,17 @@ func (r *RPCClient) Init(host string) error {
 	endPoint.Host = host
 	r.Endpoint = endPoint
 	r.DefaultClient()
-
-	// Log setup
-	log.SetFormatter(&log.JSONFormatter{})
-	log.SetOutput(os.Stdout)
-	log.SetLevel(log.DebugLevel)
 	return nil
 
 }
 
+func (r *RPCClient) logger() Logger {
+	if r.Logger == nil {
+		return NopLogger{}
+	}
+	return r.Logger
+}
+
 // SetupClient Do http client setup
 func (r *RPCClient) DefaultClient() {
 	if r.Client == nil {
@@ -92,7 +132,7 @@ func (r *RPCClient) HttpRequstURL(append string) (string, error) {
 	nodeAddr, err := r.Endpoint.DialAddress()
 
 	if err != nil {
-		log.WithFields(log.Fields{"func": "HttpRequstString"}).Error(err)
+		r.logger().WithFields(Fields{"func": "HttpRequstString"}).Error(err)
 		return "", ErrInvalidHost
 	}
 	if len(append) == 0 {
@@ -107,191 +147,338 @@ func (r *RPCClient) HttpRequstURL(append string) (string, error) {
 	return nodeAddr + "/" + append, nil
 }
 
-func (r *RPCClient) SetRPCRequest(httpMethod, query string, request []byte) (req *http.Request, err error) {
+func (r *RPCClient) SetRPCRequest(ctx context.Context, httpMethod, query string, request []byte) (req *http.Request, err error) {
 
 	if request == nil {
-		req, err = http.NewRequest(httpMethod, query, nil)
+		req, err = http.NewRequestWithContext(ctx, httpMethod, query, nil)
 	} else {
-		req, err = http.NewRequest(httpMethod, query, bytes.NewBuffer(request))
+		req, err = http.NewRequestWithContext(ctx, httpMethod, query, bytes.NewBuffer(request))
 	}
 
 	if err != nil {
-		log.WithFields(log.Fields{"func": "SetRPCRequest"}).Error(err)
+		r.logger().WithFields(Fields{"func": "SetRPCRequest"}).Error(err)
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", HTTPContentType)
+	if len(r.UserAgent) > 0 {
+		req.Header.Set("User-Agent", r.UserAgent)
+	}
+	for k, v := range r.Header {
+		req.Header.Del(k)
+		for _, value := range v {
+			req.Header.Add(k, value)
+		}
+	}
+	for k, v := range headerFromContext(ctx) {
+		req.Header.Del(k)
+		for _, value := range v {
+			req.Header.Add(k, value)
+		}
+	}
 	return req, nil
 }
 
-func (r *RPCClient) DoPostRequest(rpcReq RPCRequest) (*RPCResponse, error) {
-	r.DefaultClient()
+// DoPostRequest sends rpcReq to the endpoint through the client middlewares.
+// The request is bound to ctx, so a canceled or expired ctx aborts the round
+// trip and the body read, and the returned error is ctx.Err().
+func (r *RPCClient) DoPostRequest(ctx context.Context, rpcReq RPCRequest) (*RPCResponse, error) {
+	ctx, span := startSpan(ctx, r.tracer(), spanPrefix+rpcReq.Method)
+	span.SetAttribute(AttrRPCMethod, rpcReq.Method)
+	span.SetAttribute(AttrRPCRequestID, rpcReq.ID)
+	var resp *RPCResponse
+	var err error
+	if len(r.Middlewares) == 0 {
+		resp, err = r.doPost(ctx, &rpcReq)
+	} else {
+		resp, err = Chain(r.Middlewares...)(r.doPost)(ctx, &rpcReq)
+	}
+	rpcErr := RPCResponseError{}
+	if resp != nil {
+		rpcErr = resp.Error
+	}
+	endSpan(span, rpcErr, err)
+	return resp, err
+}
 
-	query, err := r.HttpRequstURL("")
-	if err != nil {
-		log.WithFields(log.Fields{"func": "DoPostRequest", "reqString": query}).Error(err)
-		return nil, err
+func (r *RPCClient) doPost(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error) {
+	r.applyDefaults(rpcReq)
+	key, cacheable := r.Cache.key(rpcReq)
+	if cacheable {
+		if rpcResp, ok := r.Cache.get(key, rpcReq.ID); ok {
+			return rpcResp, nil
+		}
 	}
+	rpcResp, err := r.send(ctx, rpcReq)
+	if err == nil && cacheable {
+		if err := r.Cache.put(key, rpcReq.Method, rpcResp); err != nil {
+			r.logger().WithFields(Fields{"func": "DoPostRequest", "method": rpcReq.Method}).Warn(err)
+		}
+	}
+	return rpcResp, err
+}
 
+// send posts rpcReq, retrying as the Retry policy allows
+func (r *RPCClient) send(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error) {
 	jsonParams, err := json.Marshal(rpcReq)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "DoPostRequest"}).Error(err)
+		r.logger().WithFields(Fields{"func": "DoPostRequest"}).Error(err)
 		return nil, err
 	}
-	log.WithFields(log.Fields{"func": "DoPostRequest"}).Debug(string(jsonParams))
-	req, err := r.SetRPCRequest("POST", query, jsonParams)
+	if !r.Retry.allows(rpcReq.Method) {
+		return r.doPostOnce(ctx, rpcReq, jsonParams)
+	}
+
+	var rpcResp *RPCResponse
+	err = r.Retry.do(ctx, r.logger(), rpcReq.Method, func() error {
+		rpcResp, err = r.doPostOnce(ctx, rpcReq, jsonParams)
+		if err != nil {
+			return err
+		}
+		if retryableRPCCodes[rpcResp.Error.Code] {
+			return rpcResp.Error.Err()
+		}
+		return nil
+	})
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		// out of attempts, hand the node's answer to the caller as is
+		return rpcResp, nil
+	}
 	if err != nil {
-		log.WithFields(log.Fields{"func": "DoPostRequest"}).Error(err)
 		return nil, err
 	}
+	return rpcResp, nil
+}
 
-	resp, err := r.Client.Do(req)
+func (r *RPCClient) doPostOnce(ctx context.Context, rpcReq *RPCRequest, jsonParams []byte) (*RPCResponse, error) {
+	logger := r.logger().WithFields(Fields{"func": "DoPostRequest", "method": rpcReq.Method, "id": rpcReq.ID})
+	host, err := r.HttpRequstURL("")
 	if err != nil {
-		log.WithFields(log.Fields{"func": "DoPostRequest"}).Error(err)
+		logger.Error(err)
 		return nil, err
 	}
-	defer CloseRespBody(resp)
-
-	rpcResp := new(RPCResponse)
-	body, err := io.ReadAll(resp.Body)
+	if err := r.RateLimit.Wait(ctx, host, rpcReq.Method); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	start := time.Now()
+	body, err := r.postJSON(ctx, host, jsonParams)
+	logger = logger.WithFields(Fields{"endpoint": host, "latency": time.Since(start)})
 	if err != nil {
-		log.WithFields(log.Fields{"func": "DoPostRequest"}).Error(err)
+		logger.Error(err)
+		r.observe(rpcReq.Method, host, start, len(jsonParams), 0, err)
+		r.report(host, err)
 		return nil, err
 	}
-	// log.WithFields(log.Fields{"func": "DoPostRequest"}).Debug(string(body))
+	logger.WithFields(Fields{"params": redactParams(rpcReq)}).Debug("request done")
+	rpcResp := new(RPCResponse)
 	err = json.Unmarshal(body, rpcResp)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "DoPostRequest"}).Error(err)
+		logger.Error(err)
+		r.observe(rpcReq.Method, host, start, len(jsonParams), len(body), err)
+		r.report(host, err)
 		return nil, err
 	}
+	rpcResp.Host = host
+	if rpcResp.Error.Code != 0 {
+		err = rpcResp.Error.Err()
+	}
+	r.observe(rpcReq.Method, host, start, len(jsonParams), len(body), err)
+	if retryableRPCCodes[rpcResp.Error.Code] {
+		r.report(host, rpcResp.Error.Err())
+	} else {
+		r.report(host, nil)
+	}
 	return rpcResp, nil
 }
 
-func (r *RPCClient) CheckHealth() bool {
+// postJSON posts an encoded request (single or batch) to query and returns
+// the raw response body
+func (r *RPCClient) postJSON(parent context.Context, query string, payload []byte) ([]byte, error) {
+	r.DefaultClient()
+	ctx := parent
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, r.Timeout)
+		defer cancel()
+	}
+
+	span := spanFromContext(ctx)
+	span.SetAttribute(AttrEndpoint, endpointLabel(query))
+	req, err := r.SetRPCRequest(ctx, "POST", query, payload)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "postJSON"}).Error(err)
+		return nil, err
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "postJSON"}).Error(err)
+		return nil, requestError(parent, ctx, err)
+	}
+	defer CloseRespBody(resp)
+	span.SetAttribute(AttrHTTPStatusCode, resp.StatusCode)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		httpErr := &HTTPError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		if errBody, err := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodySize)); err == nil {
+			httpErr.Body = string(errBody)
+		}
+		if httpErr.StatusCode == http.StatusTooManyRequests {
+			r.RateLimit.Throttle(query, httpErr.RetryAfter)
+		}
+		r.logger().WithFields(Fields{"func": "postJSON"}).Error(httpErr)
+		return nil, httpErr
+	}
+	if err := checkContentType(resp.Header.Get("Content-Type")); err != nil {
+		r.logger().WithFields(Fields{"func": "postJSON", "contentType": resp.Header.Get("Content-Type")}).Error(err)
+		return nil, err
+	}
+
+	maxSize := r.MaxResponseSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxResponseSize
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "postJSON"}).Error(err)
+		return nil, requestError(parent, ctx, err)
+	}
+	if int64(len(body)) > maxSize {
+		r.logger().WithFields(Fields{"func": "postJSON", "limit": maxSize}).Error(ErrResponseTooLarge)
+		return nil, ErrResponseTooLarge
+	}
+	return body, nil
+}
+
+func checkContentType(contentType string) error {
+	if len(contentType) == 0 {
+		return ErrNOContentType
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != HTTPContentType {
+		return ErrNotJSONType
+	}
+	return nil
+}
+
+// report tells an EndpointReporter how a request to host went. Cancellation
+// by the caller says nothing about the host and is not reported.
+func (r *RPCClient) report(host string, err error) {
+	reporter, ok := r.Endpoint.(EndpointReporter)
+	if !ok || len(host) == 0 {
+		return
+	}
+	if err == nil {
+		reporter.ReportSuccess(host)
+		return
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	reporter.ReportFailure(host, err)
+}
+
+// CheckHealth returns true when the node answers "ok" on /health before ctx is done
+func (r *RPCClient) CheckHealth(ctx context.Context) bool {
 	r.DefaultClient()
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
 
 	query, err := r.HttpRequstURL("health")
 	if err != nil {
-		log.WithFields(log.Fields{"func": "CheckHealth", "info": query}).Error(err)
+		r.logger().WithFields(Fields{"func": "CheckHealth", "info": query}).Error(err)
 		return false
 	}
 
-	req, err := r.SetRPCRequest("GET", query, nil)
+	req, err := r.SetRPCRequest(ctx, "GET", query, nil)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "CheckHealth"}).Error(err)
+		r.logger().WithFields(Fields{"func": "CheckHealth"}).Error(err)
 		return false
 	}
-	log.WithFields(log.Fields{"func": "CheckHealth"}).Debug(*req)
+	r.logger().WithFields(Fields{"func": "CheckHealth"}).Debug(*req)
 	resp, err := r.Client.Do(req)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "CheckHealth"}).Error(err)
+		r.logger().WithFields(Fields{"func": "CheckHealth"}).Error(err)
 		return false
 	}
 	defer CloseRespBody(resp)
 
 	cont, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "CheckHealth"}).Error(err)
+		r.logger().WithFields(Fields{"func": "CheckHealth"}).Error(err)
 		return false
 	}
 	if strings.Compare(string(cont), "ok") == 0 {
-		log.WithFields(log.Fields{"func": "CheckHealth"}).Debug("OK")
+		r.logger().WithFields(Fields{"func": "CheckHealth"}).Debug("OK")
 		return true
 	}
 	return false
 }
 
-func (r *RPCClient) GetAccountInfo(publicKey string, extra *AccountInfoExtraParams) (*RPCResponse, error) {
-
-	if len(publicKey) == 0 {
-		return nil, ErrInvalidFuncParameter
-	}
-	// Construct Query Params
+// Call sends method with params and decodes the result into result, which may
+// be nil when only errors matter. An error member is returned as an error. A
+// {context, value} result is unwrapped into result, unless result is a
+// *ContextResult which also receives the context. A null result or value is
+// ErrNullResult.
+func (r *RPCClient) Call(ctx context.Context, method string, params []interface{}, result interface{}) error {
 	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getAccountInfo"}
-	rpcReq.Params = append(rpcReq.Params, publicKey)
-	if extra != nil {
-		rpcReq.Params = append(rpcReq.Params, extra)
-	}
-
-	resp, err := r.DoPostRequest(rpcReq)
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: method, Params: params}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "GetAccountInfo"}).Error(err)
-		return nil, err
+		r.logger().WithFields(Fields{"func": "Call", "method": method}).Error(err)
+		return err
 	}
 	if resp.ID != id {
-		return nil, ErrIDMismatch
+		return ErrIDMismatch
 	}
-	return resp, nil
-}
-
-func (r *RPCClient) GetBalance(publicKey string) (*RPCResponse, error) {
-	if len(publicKey) == 0 {
-		return nil, ErrInvalidFuncParameter
+	if err := resp.Error.Err(); err != nil {
+		return err
 	}
-	// Construct Query Params
-	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getBalance"}
-	rpcReq.Params = append(rpcReq.Params, publicKey)
-	resp, err := r.DoPostRequest(rpcReq)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "GetBalance"}).Error(err)
-		return nil, err
+	if result == nil {
+		return nil
 	}
-	if resp.ID != id {
-		return nil, ErrIDMismatch
+	if isNullJSON(resp.Result) {
+		return ErrNullResult
 	}
-	return resp, nil
-}
-
-func (r *RPCClient) GetBlockCommitment(block uint64) (*RPCResponse, error) {
-	// Construct Query Params
-	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getBlockCommitment"}
-	rpcReq.Params = append(rpcReq.Params, block)
-	resp, err := r.DoPostRequest(rpcReq)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "GetBalance"}).Error(err)
-		return nil, err
+	wrapped, ok := result.(*ContextResult)
+	if !ok && isContextResult(resp.Result) {
+		wrapped, ok = &ContextResult{Value: result}, true
 	}
-	if resp.ID != id {
-		return nil, ErrIDMismatch
+	if !ok {
+		return json.Unmarshal(resp.Result, result)
 	}
-	return resp, nil
-}
-
-func (r *RPCClient) GetBlockTime(block uint64) (*RPCResponse, error) {
-	// Construct Query Params
-	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getBlockTime"}
-	rpcReq.Params = append(rpcReq.Params, block)
-	resp, err := r.DoPostRequest(rpcReq)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "GetBalance"}).Error(err)
-		return nil, err
+	if err := json.Unmarshal(resp.Result, wrapped); err != nil {
+		return err
 	}
-	if resp.ID != id {
-		return nil, ErrIDMismatch
+	if wrapped.Value == nil {
+		return ErrNullResult
 	}
-	return resp, nil
+	return nil
 }
 
-func (r *RPCClient) GetClusterNodes() (*RPCResponse, error) {
-	// Construct Query Params
-	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getClusterNodes"}
-	resp, err := r.DoPostRequest(rpcReq)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "GetClusterNodes"}).Error(err)
-		return nil, err
+// isContextResult reports whether raw is an object made of context and value only
+func isContextResult(raw json.RawMessage) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		return false
 	}
-	if resp.ID != id {
-		return nil, ErrIDMismatch
+	fields := map[string]json.RawMessage{}
+	if json.Unmarshal(raw, &fields) != nil || len(fields) != 2 {
+		return false
 	}
-	return resp, nil
+	_, hasContext := fields["context"]
+	_, hasValue := fields["value"]
+	return hasContext && hasValue
 }
 
-func (r *RPCClient) GetConfirmedBlock(params *ConfirmedBlockParam) (*RPCResponse, error) {
+// The wrappers of the methods in methods.json are generated into api_gen.go.
+// The ones below take params the spec cannot describe.
+
+func (r *RPCClient) GetConfirmedBlock(ctx context.Context, params *ConfirmedBlockParam) (*RPCResponse, error) {
 	// Construct Query Params
 	id := RandomID()
 	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getConfirmedBlock"}
@@ -301,9 +488,9 @@ func (r *RPCClient) GetConfirmedBlock(params *ConfirmedBlockParam) (*RPCResponse
 	if params.ConfirmedBlockParamObj != emptyParamObj {
 		rpcReq.Params = append(rpcReq.Params, params.ConfirmedBlockParamObj)
 	}
-	resp, err := r.DoPostRequest(rpcReq)
+	resp, err := r.DoPostRequest(ctx, rpcReq)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "GetConfirmedBlock"}).Error(err)
+		r.logger().WithFields(Fields{"func": "GetConfirmedBlock"}).Error(err)
 		return nil, err
 	}
 	if resp.ID != id {
@@ -312,27 +499,7 @@ func (r *RPCClient) GetConfirmedBlock(params *ConfirmedBlockParam) (*RPCResponse
 	return resp, nil
 }
 
-// GetBlockProduction --- > Method not found --- Deprecate !?
-// set params = nil  for default settings
-func (r *RPCClient) GetBlockProduction(params *BlockProductionQueryParam) (*RPCResponse, error) {
-	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getBlockProduction"}
-	if params != nil {
-		rpcReq.Params = append(rpcReq.Params, *params)
-	}
-
-	resp, err := r.DoPostRequest(rpcReq)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "GetBlockProduction"}).Error(err)
-		return nil, err
-	}
-	if resp.ID != id {
-		return nil, ErrIDMismatch
-	}
-	return resp, nil
-}
-
-func (r *RPCClient) GetConfirmedBlocks(params *ConfirmedBlocksParam) (*RPCResponse, error) {
+func (r *RPCClient) GetConfirmedBlocks(ctx context.Context, params *ConfirmedBlocksParam) (*RPCResponse, error) {
 	id := RandomID()
 	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getConfirmedBlocks"}
 
@@ -347,9 +514,9 @@ func (r *RPCClient) GetConfirmedBlocks(params *ConfirmedBlocksParam) (*RPCRespon
 	if len(params.CommitmentConfig.Commitment) > 0 {
 		rpcReq.Params = append(rpcReq.Params, params.CommitmentConfig)
 	}
-	resp, err := r.DoPostRequest(rpcReq)
+	resp, err := r.DoPostRequest(ctx, rpcReq)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "GetConfirmedBlocks"}).Error(err)
+		r.logger().WithFields(Fields{"func": "GetConfirmedBlocks"}).Error(err)
 		return nil, err
 	}
 	if resp.ID != id {
@@ -358,7 +525,7 @@ func (r *RPCClient) GetConfirmedBlocks(params *ConfirmedBlocksParam) (*RPCRespon
 	return resp, nil
 }
 
-func (r *RPCClient) GetConfirmedBlocksWithLimit(params *ConfirmedBlocksWithLimitParam) (*RPCResponse, error) {
+func (r *RPCClient) GetConfirmedBlocksWithLimit(ctx context.Context, params *ConfirmedBlocksWithLimitParam) (*RPCResponse, error) {
 	id := RandomID()
 	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getConfirmedBlocksWithLimit"}
 
@@ -369,77 +536,9 @@ func (r *RPCClient) GetConfirmedBlocksWithLimit(params *ConfirmedBlocksWithLimit
 	if len(params.CommitmentConfig.Commitment) > 0 {
 		rpcReq.Params = append(rpcReq.Params, params.CommitmentConfig)
 	}
-	resp, err := r.DoPostRequest(rpcReq)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "GetConfirmedBlocksWithLimit"}).Error(err)
-		return nil, err
-	}
-	if resp.ID != id {
-		return nil, ErrIDMismatch
-	}
-	return resp, nil
-}
-
-func (r *RPCClient) GetConfirmedSignaturesForAddress2(base58Sig string, extra *ConfirmedSignaturesForAddress2ParamExtra) (*RPCResponse, error) {
-	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getConfirmedSignaturesForAddress2"}
-
-	if len(base58Sig) == 0 {
-		return nil, ErrInvalidFuncParameter
-	}
-	rpcReq.Params = append(rpcReq.Params, base58Sig)
-	if extra != nil {
-		rpcReq.Params = append(rpcReq.Params, extra)
-	}
-	resp, err := r.DoPostRequest(rpcReq)
+	resp, err := r.DoPostRequest(ctx, rpcReq)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "GetConfirmedSignaturesForAddress2"}).Error(err)
-		return nil, err
-	}
-	if resp.ID != id {
-		return nil, ErrIDMismatch
-	}
-	return resp, nil
-}
-
-func (r *RPCClient) GetTokenSupply(base58Pubkey string, commitment *CommitmentConfig) (*RPCResponse, error) {
-	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getTokenSupply"}
-
-	if len(base58Pubkey) == 0 {
-		return nil, ErrInvalidFuncParameter
-	}
-	rpcReq.Params = append(rpcReq.Params, base58Pubkey)
-
-	if commitment != nil && len(commitment.Commitment) > 0 {
-		rpcReq.Params = append(rpcReq.Params, *commitment)
-	}
-	resp, err := r.DoPostRequest(rpcReq)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "GetTokenSupply"}).Error(err)
-		return nil, err
-	}
-	if resp.ID != id {
-		return nil, ErrIDMismatch
-	}
-	return resp, nil
-}
-
-func (r *RPCClient) GetTokenAccountBalance(base58Pubkey string, commitment *CommitmentConfig) (*RPCResponse, error) {
-	id := RandomID()
-	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getTokenAccountBalance"}
-
-	if len(base58Pubkey) == 0 {
-		return nil, ErrInvalidFuncParameter
-	}
-	rpcReq.Params = append(rpcReq.Params, base58Pubkey)
-
-	if commitment != nil && len(commitment.Commitment) > 0 {
-		rpcReq.Params = append(rpcReq.Params, *commitment)
-	}
-	resp, err := r.DoPostRequest(rpcReq)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "GetTokenAccountBalance"}).Error(err)
+		r.logger().WithFields(Fields{"func": "GetConfirmedBlocksWithLimit"}).Error(err)
 		return nil, err
 	}
 	if resp.ID != id {
@@ -449,7 +548,7 @@ func (r *RPCClient) GetTokenAccountBalance(base58Pubkey string, commitment *Comm
 }
 
 // TODO: Test on a real pair of (pubKey and programId)
-func (r *RPCClient) GetTokenAccountsByDelegate(base58Pubkey string, addrOrID interface{}, extra *TokenAccountsByDelegateParamExtra) (*RPCResponse, error) {
+func (r *RPCClient) GetTokenAccountsByDelegate(ctx context.Context, base58Pubkey string, addrOrID interface{}, extra *TokenAccountsByDelegateParamExtra) (*RPCResponse, error) {
 	id := RandomID()
 	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getTokenAccountsByDelegate"}
 
@@ -470,9 +569,9 @@ func (r *RPCClient) GetTokenAccountsByDelegate(base58Pubkey string, addrOrID int
 	if extra != nil {
 		rpcReq.Params = append(rpcReq.Params, *extra)
 	}
-	resp, err := r.DoPostRequest(rpcReq)
+	resp, err := r.DoPostRequest(ctx, rpcReq)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "GetTokenAccountsByDelegate"}).Error(err)
+		r.logger().WithFields(Fields{"func": "GetTokenAccountsByDelegate"}).Error(err)
 		return nil, err
 	}
 	if resp.ID != id {
@@ -480,3 +579,45 @@ func (r *RPCClient) GetTokenAccountsByDelegate(base58Pubkey string, addrOrID int
 	}
 	return resp, nil
 }
+
+// GetSignatureStatuses asks for the status of signatures, MaxSignatureStatuses
+// at a time. The responses are those of each chunk in order; parse them with
+// ParseSignatureStatuses.
+func (r *RPCClient) GetSignatureStatuses(ctx context.Context, signatures []string, extra *SignatureStatusesParamExtra) ([]*RPCResponse, error) {
+	if len(signatures) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	chunks := signatureChunks(signatures)
+	resps := make([]*RPCResponse, 0, len(chunks))
+	for _, chunk := range chunks {
+		id := RandomID()
+		rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getSignatureStatuses"}
+		rpcReq.Params = append(rpcReq.Params, chunk)
+		if extra != nil && extra.SearchTransactionHistory {
+			rpcReq.Params = append(rpcReq.Params, *extra)
+		}
+		resp, err := r.DoPostRequest(ctx, rpcReq)
+		if err != nil {
+			r.logger().WithFields(Fields{"func": "GetSignatureStatuses"}).Error(err)
+			return nil, err
+		}
+		if resp.ID != id {
+			return nil, ErrIDMismatch
+		}
+		resps = append(resps, resp)
+	}
+	return resps, nil
+}
+
+// signatureChunks splits signatures into the requests of GetSignatureStatuses
+func signatureChunks(signatures []string) [][]string {
+	chunks := make([][]string, 0, (len(signatures)+MaxSignatureStatuses-1)/MaxSignatureStatuses)
+	for start := 0; start < len(signatures); start += MaxSignatureStatuses {
+		end := start + MaxSignatureStatuses
+		if end > len(signatures) {
+			end = len(signatures)
+		}
+		chunks = append(chunks, signatures[start:end])
+	}
+	return chunks
+}
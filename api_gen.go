@@ -0,0 +1,204 @@
+// Code generated by cmd/genapi from methods.json. DO NOT EDIT.
+
+package solanarpc
+
+import "context"
+
+// GetAccountInfo sends getAccountInfo, whose response ParseAccountInfoResponse parses.
+func (r *RPCClient) GetAccountInfo(ctx context.Context, publicKey string, extra *AccountInfoExtraParams) (*RPCResponse, error) {
+	if len(publicKey) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getAccountInfo"}
+	rpcReq.Params = append(rpcReq.Params, publicKey)
+	if extra != nil && !isEmptyConfig(*extra) {
+		rpcReq.Params = append(rpcReq.Params, *extra)
+	}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetAccountInfo"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetBalance sends getBalance, whose response ParseBalanceResponse parses.
+func (r *RPCClient) GetBalance(ctx context.Context, publicKey string) (*RPCResponse, error) {
+	if len(publicKey) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getBalance"}
+	rpcReq.Params = append(rpcReq.Params, publicKey)
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetBalance"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetBlockCommitment sends getBlockCommitment, whose response ParseBlockCommitmentResponse parses.
+func (r *RPCClient) GetBlockCommitment(ctx context.Context, block uint64) (*RPCResponse, error) {
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getBlockCommitment"}
+	rpcReq.Params = append(rpcReq.Params, block)
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetBlockCommitment"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetBlockTime sends getBlockTime, whose response ParseBlockTimeResponse parses.
+func (r *RPCClient) GetBlockTime(ctx context.Context, block uint64) (*RPCResponse, error) {
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getBlockTime"}
+	rpcReq.Params = append(rpcReq.Params, block)
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetBlockTime"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetClusterNodes sends getClusterNodes, whose response ParseClusterNodesResponse parses.
+func (r *RPCClient) GetClusterNodes(ctx context.Context) (*RPCResponse, error) {
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getClusterNodes"}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetClusterNodes"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetBlockProduction sends getBlockProduction. Set params to nil for the default settings.
+func (r *RPCClient) GetBlockProduction(ctx context.Context, params *BlockProductionQueryParam) (*RPCResponse, error) {
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getBlockProduction"}
+	if params != nil && !isEmptyConfig(*params) {
+		rpcReq.Params = append(rpcReq.Params, *params)
+	}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetBlockProduction"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetConfirmedSignaturesForAddress2 sends getConfirmedSignaturesForAddress2, whose response ParseConfirmedSignaturesForAddress2 parses.
+func (r *RPCClient) GetConfirmedSignaturesForAddress2(ctx context.Context, base58Sig string, extra *ConfirmedSignaturesForAddress2ParamExtra) (*RPCResponse, error) {
+	if len(base58Sig) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getConfirmedSignaturesForAddress2"}
+	rpcReq.Params = append(rpcReq.Params, base58Sig)
+	if extra != nil && !isEmptyConfig(*extra) {
+		rpcReq.Params = append(rpcReq.Params, *extra)
+	}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetConfirmedSignaturesForAddress2"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetTokenSupply sends getTokenSupply, whose response ParseTokenSupply parses.
+func (r *RPCClient) GetTokenSupply(ctx context.Context, base58Pubkey string, commitment *CommitmentConfig) (*RPCResponse, error) {
+	if len(base58Pubkey) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getTokenSupply"}
+	rpcReq.Params = append(rpcReq.Params, base58Pubkey)
+	if commitment != nil && !isEmptyConfig(*commitment) {
+		rpcReq.Params = append(rpcReq.Params, *commitment)
+	}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetTokenSupply"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetTokenAccountBalance sends getTokenAccountBalance, whose response ParseTokenAccountBalance parses.
+func (r *RPCClient) GetTokenAccountBalance(ctx context.Context, base58Pubkey string, commitment *CommitmentConfig) (*RPCResponse, error) {
+	if len(base58Pubkey) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getTokenAccountBalance"}
+	rpcReq.Params = append(rpcReq.Params, base58Pubkey)
+	if commitment != nil && !isEmptyConfig(*commitment) {
+		rpcReq.Params = append(rpcReq.Params, *commitment)
+	}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetTokenAccountBalance"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+
+// GetTransaction sends getTransaction, whose response ParseTransactionResponse parses. A node that does not know it is sent getConfirmedTransaction instead.
+func (r *RPCClient) GetTransaction(ctx context.Context, signature string, config *TransactionParamObj) (*RPCResponse, error) {
+	if len(signature) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "getTransaction"}
+	rpcReq.Params = append(rpcReq.Params, signature)
+	if config != nil && !isEmptyConfig(*config) {
+		rpcReq.Params = append(rpcReq.Params, *config)
+	}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+	if err == nil && resp.ID == id && resp.Error.Code == ErrCodeMethodNotFound {
+		id = RandomID()
+		rpcReq.ID, rpcReq.Method = id, "getConfirmedTransaction"
+		resp, err = r.DoPostRequest(ctx, rpcReq)
+	}
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetTransaction"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
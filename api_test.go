@@ -0,0 +1,113 @@
+package solanarpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoPostRequestContextDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(500 * time.Millisecond):
+		}
+	}))
+	defer srv.Close()
+
+	client := RPCClient{}
+	assert.NoError(t, client.Init(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "deadline error expected, got %v", err)
+}
+
+func TestCheckHealthCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := RPCClient{}
+	assert.NoError(t, client.Init(srv.URL))
+	assert.True(t, client.CheckHealth(context.Background()))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, client.CheckHealth(ctx))
+}
+
+func TestDoPostRequestValidatesHTTPResponse(t *testing.T) {
+	var handle func(w http.ResponseWriter)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handle(w)
+	}))
+	defer srv.Close()
+	client := RPCClient{MaxResponseSize: 64}
+	assert.NoError(t, client.Init(srv.URL))
+	ctx := context.Background()
+
+	handle = func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>" + strings.Repeat("x", 2*MaxErrorBodySize) + "</html>"))
+	}
+	_, err := client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	httpErr := new(HTTPError)
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	assert.Len(t, httpErr.Body, MaxErrorBodySize)
+
+	handle = func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}
+	_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Equal(t, ErrNotJSONType, err)
+
+	handle = func(w http.ResponseWriter) {
+		w.Header()["Content-Type"] = nil
+		w.Write([]byte(`{}`))
+	}
+	_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Equal(t, ErrNOContentType, err)
+
+	handle = func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"jsonrpc":"2.0","result":"` + strings.Repeat("x", 64) + `","id":1}`))
+	}
+	_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Equal(t, ErrResponseTooLarge, err)
+}
+
+func TestDoPostRequestEndlessBody(t *testing.T) {
+	var written int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", HTTPContentType)
+		chunk := []byte(strings.Repeat("x", 32<<10))
+		for {
+			n, err := w.Write(chunk)
+			atomic.AddInt64(&written, int64(n))
+			if err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+	client := RPCClient{MaxResponseSize: 1 << 10}
+	assert.NoError(t, client.Init(srv.URL))
+
+	start := time.Now()
+	_, err := client.GetBalance(context.Background(), "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Equal(t, ErrResponseTooLarge, err)
+	// the rest of the body is dropped with the connection, not read
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Less(t, atomic.LoadInt64(&written), int64(64<<20))
+}
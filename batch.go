@@ -0,0 +1,150 @@
+package solanarpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// NewRPCRequest builds a request with a random ID, the same way the Get* wrappers do
+func NewRPCRequest(method string, params ...interface{}) RPCRequest {
+	rpcReq := RPCRequest{Version: RequestVersion, ID: RandomID(), Method: method}
+	rpcReq.Params = append(rpcReq.Params, params...)
+	return rpcReq
+}
+
+// DoBatchRequest sends rpcReqs as JSON-RPC 2.0 batches of at most BatchSize
+// requests each. Responses are matched back by ID and returned in the order of
+// rpcReqs, carrying the caller's IDs. A failed item is reported in its own
+// RPCResponse.Error and does not fail the batch.
+func (r *RPCClient) DoBatchRequest(ctx context.Context, rpcReqs []RPCRequest) ([]*RPCResponse, error) {
+	if len(rpcReqs) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	ctx, span := startSpan(ctx, r.tracer(), spanPrefix+"batch")
+	span.SetAttribute(AttrBatchSize, len(rpcReqs))
+	resps, err := r.doBatch(ctx, rpcReqs)
+	endSpan(span, RPCResponseError{}, err)
+	return resps, err
+}
+
+func (r *RPCClient) doBatch(ctx context.Context, rpcReqs []RPCRequest) ([]*RPCResponse, error) {
+	size := r.BatchSize
+	if size <= 0 {
+		size = MaxBatchSize
+	}
+
+	resps := make([]*RPCResponse, 0, len(rpcReqs))
+	for start := 0; start < len(rpcReqs); start += size {
+		end := start + size
+		if end > len(rpcReqs) {
+			end = len(rpcReqs)
+		}
+		chunkReqs := rpcReqs[start:end]
+		var chunk []*RPCResponse
+		var err error
+		if r.Retry.allows(batchMethods(chunkReqs)...) {
+			err = r.Retry.do(ctx, r.logger(), "batch", func() error {
+				chunk, err = r.doBatchChunk(ctx, chunkReqs)
+				return err
+			})
+		} else {
+			chunk, err = r.doBatchChunk(ctx, chunkReqs)
+		}
+		if err != nil {
+			r.logger().WithFields(Fields{"func": "DoBatchRequest"}).Error(err)
+			return nil, err
+		}
+		resps = append(resps, chunk...)
+	}
+	return resps, nil
+}
+
+// doBatchChunk posts one batch. Caller IDs are random and may collide, so the
+// batch goes out with sequential IDs which are swapped back on the way in.
+func (r *RPCClient) doBatchChunk(ctx context.Context, rpcReqs []RPCRequest) ([]*RPCResponse, error) {
+	wireReqs := make([]RPCRequest, len(rpcReqs))
+	for i, rpcReq := range rpcReqs {
+		wireReqs[i] = rpcReq
+		wireReqs[i].ID = uint64(i + 1)
+		r.applyDefaults(&wireReqs[i])
+	}
+	jsonParams, err := json.Marshal(wireReqs)
+	if err != nil {
+		return nil, err
+	}
+	host, err := r.HttpRequstURL("")
+	if err != nil {
+		return nil, err
+	}
+	for _, rpcReq := range rpcReqs {
+		if err := r.RateLimit.Wait(ctx, host, rpcReq.Method); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	body, err := r.postJSON(ctx, host, jsonParams)
+	r.observe("batch", host, start, len(jsonParams), len(body), err)
+	r.report(host, err)
+	if err != nil {
+		return nil, err
+	}
+
+	// A malformed batch is answered with a single error object instead of an array
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		rpcResp := new(RPCResponse)
+		if err := json.Unmarshal(trimmed, rpcResp); err != nil {
+			return nil, err
+		}
+		if rpcResp.Error.Code != 0 {
+			return nil, rpcResp.Error.Err()
+		}
+		return nil, ErrJSONParseError
+	}
+
+	wireResps := []struct {
+		RPCResponse
+		ID *uint64 `json:"id"`
+	}{}
+	if err := json.Unmarshal(body, &wireResps); err != nil {
+		return nil, err
+	}
+	resps := make([]*RPCResponse, len(rpcReqs))
+	// an item the node could not read, e.g. an invalid request, is answered
+	// with a null id; those answers go to the unanswered items in order
+	unmatched := []*RPCResponse{}
+	for i := range wireResps {
+		resp := &wireResps[i].RPCResponse
+		resp.Host = host
+		if wireResps[i].ID == nil {
+			unmatched = append(unmatched, resp)
+			continue
+		}
+		idx := int(*wireResps[i].ID) - 1
+		if idx < 0 || idx >= len(rpcReqs) || resps[idx] != nil {
+			return nil, ErrIDMismatch
+		}
+		resp.ID = rpcReqs[idx].ID
+		resps[idx] = resp
+	}
+	for idx, resp := range resps {
+		if resp != nil {
+			continue
+		}
+		if len(unmatched) == 0 {
+			return nil, ErrBatchResponseMissing
+		}
+		resps[idx], unmatched = unmatched[0], unmatched[1:]
+		resps[idx].ID = rpcReqs[idx].ID
+	}
+	return resps, nil
+}
+
+func batchMethods(rpcReqs []RPCRequest) []string {
+	methods := make([]string, len(rpcReqs))
+	for i, rpcReq := range rpcReqs {
+		methods[i] = rpcReq.Method
+	}
+	return methods
+}
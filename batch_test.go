@@ -0,0 +1,100 @@
+package solanarpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// reverseBatchHandler answers a batch in reverse order and fails every getBlockTime
+func reverseBatchHandler(posts *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		*posts++
+		reqs := []RPCRequest{}
+		json.NewDecoder(req.Body).Decode(&reqs)
+		resps := []RPCResponse{}
+		for i := len(reqs) - 1; i >= 0; i-- {
+			resp := RPCResponse{Version: RequestVersion, ID: reqs[i].ID}
+			if reqs[i].Method == "getBlockTime" {
+				resp.Error = RPCResponseError{Code: -32004, Message: "Block not available"}
+			} else {
+				resp.Result = json.RawMessage(fmt.Sprintf(`{"context":{"slot":1},"value":%d}`, i))
+			}
+			resps = append(resps, resp)
+		}
+		w.Header().Set("Content-Type", HTTPContentType)
+		json.NewEncoder(w).Encode(resps)
+	}
+}
+
+func TestDoBatchRequest(t *testing.T) {
+	posts := 0
+	srv := httptest.NewServer(reverseBatchHandler(&posts))
+	defer srv.Close()
+
+	client := RPCClient{BatchSize: 2}
+	assert.NoError(t, client.Init(srv.URL))
+	reqs := []RPCRequest{
+		NewRPCRequest("getBalance", "a"),
+		NewRPCRequest("getBlockTime", 5),
+		NewRPCRequest("getBalance", "b"),
+		NewRPCRequest("getBalance", "c"),
+		NewRPCRequest("getBalance", "d"),
+	}
+	reqs[2].ID = reqs[0].ID
+	resps, err := client.DoBatchRequest(context.Background(), reqs)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, posts, "batch should be split into chunks")
+	assert.Len(t, resps, len(reqs))
+	for i, resp := range resps {
+		assert.Equal(t, reqs[i].ID, resp.ID)
+	}
+	assert.Equal(t, -32004, resps[1].Error.Code)
+	balance, _, err := ParseBalanceResponse(resps[3])
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), balance)
+}
+
+func TestDoBatchRequestInvalidItem(t *testing.T) {
+	body := `[{"jsonrpc":"2.0","result":1,"id":1},{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid request"},"id":null},{"jsonrpc":"2.0","result":3,"id":3}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", HTTPContentType)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	client := RPCClient{}
+	assert.NoError(t, client.Init(srv.URL))
+	reqs := []RPCRequest{NewRPCRequest("getSlot"), NewRPCRequest(""), NewRPCRequest("getSlot")}
+	resps, err := client.DoBatchRequest(context.Background(), reqs)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", string(resps[0].Result))
+	assert.Equal(t, reqs[1].ID, resps[1].ID)
+	assert.Equal(t, ErrCodeInvalidRequest, resps[1].Error.Code)
+	assert.Equal(t, "3", string(resps[2].Result))
+
+	// a known id answered twice is still a mismatch
+	body = `[{"jsonrpc":"2.0","result":1,"id":1},{"jsonrpc":"2.0","result":1,"id":1},{"jsonrpc":"2.0","result":3,"id":3}]`
+	_, err = client.DoBatchRequest(context.Background(), reqs)
+	assert.Equal(t, ErrIDMismatch, err)
+}
+
+func TestDoBatchRequestMissingResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", HTTPContentType)
+		w.Write([]byte(`[{"jsonrpc":"2.0","result":1,"id":1}]`))
+	}))
+	defer srv.Close()
+
+	client := RPCClient{}
+	assert.NoError(t, client.Init(srv.URL))
+	_, err := client.DoBatchRequest(context.Background(), []RPCRequest{NewRPCRequest("getSlot"), NewRPCRequest("getSlot")})
+	assert.Equal(t, ErrBatchResponseMissing, err)
+	_, err = client.DoBatchRequest(context.Background(), nil)
+	assert.Equal(t, ErrInvalidFuncParameter, err)
+}
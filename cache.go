@@ -0,0 +1,321 @@
+package solanarpc
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheEntries bounds a MemoryCacheStore created with a size <= 0
+const DefaultCacheEntries = 1024
+
+// CacheableMethods return results that never change once their block is
+// finalized. getConfirmedSignaturesForAddress2 only qualifies for pages
+// anchored with before, the newest page grows with every transaction.
+var CacheableMethods = map[string]bool{
+	"getBlock":                          true,
+	"getBlockTime":                      true,
+	"getConfirmedBlock":                 true,
+	"getConfirmedSignaturesForAddress2": true,
+	"getConfirmedTransaction":           true,
+	"getSignaturesForAddress":           true,
+	"getTransaction":                    true,
+}
+
+// CacheStore keeps encoded responses by key. Implementations must be safe
+// for concurrent use.
+type CacheStore interface {
+	// Get returns the value of key unless it is missing or expired
+	Get(key string) ([]byte, bool)
+	// Set stores value under key; ttl <= 0 keeps it until it is evicted
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// ResponseCache answers requests for CacheableMethods at finalized commitment
+// from Store. Only successful, non-null results are stored. Batches bypass it.
+type ResponseCache struct {
+	Store CacheStore
+	// TTL expires entries; 0 keeps them until evicted
+	TTL time.Duration
+	// MethodTTL overrides TTL for single methods
+	MethodTTL map[string]time.Duration
+
+	hits   uint64
+	misses uint64
+}
+
+// CacheStats counts lookups of cacheable requests
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// NewResponseCache caches in store, or in a MemoryCacheStore of
+// DefaultCacheEntries when store is nil
+func NewResponseCache(store CacheStore) *ResponseCache {
+	if store == nil {
+		store = NewMemoryCacheStore(DefaultCacheEntries)
+	}
+	return &ResponseCache{Store: store}
+}
+
+func (c *ResponseCache) Stats() CacheStats {
+	return CacheStats{Hits: atomic.LoadUint64(&c.hits), Misses: atomic.LoadUint64(&c.misses)}
+}
+
+// key returns the cache key of rpcReq: its method and canonical params.
+// ok is false when rpcReq may not be cached.
+func (c *ResponseCache) key(rpcReq *RPCRequest) (key string, ok bool) {
+	if c == nil || c.Store == nil || !CacheableMethods[rpcReq.Method] {
+		return "", false
+	}
+	config := map[string]interface{}{}
+	for _, param := range rpcReq.Params {
+		if object := asObject(param); object != nil {
+			config = object
+		}
+	}
+	if commitment, _ := config["commitment"].(string); !isFinalized(commitment) {
+		return "", false
+	}
+	if rpcReq.Method == "getConfirmedSignaturesForAddress2" || rpcReq.Method == "getSignaturesForAddress" {
+		if before, _ := config["before"].(string); len(before) == 0 {
+			return "", false
+		}
+	}
+	params, err := canonicalJSON(rpcReq.Params)
+	if err != nil {
+		return "", false
+	}
+	return rpcReq.Method + ":" + string(params), true
+}
+
+// isFinalized reports whether commitment, as sent, asks for finalized data.
+// An empty commitment leaves the node default, which is finalized.
+func isFinalized(commitment string) bool {
+	switch strings.ToLower(commitment) {
+	case "", string(Finalized), "max", "root":
+		return true
+	}
+	return false
+}
+
+// canonicalJSON encodes v with object keys sorted, so equal params written
+// as structs or maps give the same key
+func canonicalJSON(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := unmarshalNumbers(raw, &generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
+
+func (c *ResponseCache) get(key string, id uint64) (*RPCResponse, bool) {
+	raw, ok := c.Store.Get(key)
+	rpcResp := new(RPCResponse)
+	if !ok || json.Unmarshal(raw, rpcResp) != nil {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&c.hits, 1)
+	rpcResp.ID = id
+	return rpcResp, true
+}
+
+func (c *ResponseCache) put(key, method string, rpcResp *RPCResponse) error {
+	if rpcResp.Error.Code != 0 || len(rpcResp.Result) == 0 || string(rpcResp.Result) == "null" {
+		return nil
+	}
+	raw, err := json.Marshal(rpcResp)
+	if err != nil {
+		return err
+	}
+	ttl := c.TTL
+	if methodTTL, ok := c.MethodTTL[method]; ok {
+		ttl = methodTTL
+	}
+	return c.Store.Set(key, raw, ttl)
+}
+
+func expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+func expired(expires time.Time) bool {
+	return !expires.IsZero() && time.Now().After(expires)
+}
+
+// MemoryCacheStore is an in-memory CacheStore evicting the least recently
+// used entry beyond its size
+type MemoryCacheStore struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func NewMemoryCacheStore(size int) *MemoryCacheStore {
+	if size <= 0 {
+		size = DefaultCacheEntries
+	}
+	return &MemoryCacheStore{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (s *MemoryCacheStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryCacheEntry)
+	if expired(entry.expires) {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+		return nil, false
+	}
+	s.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (s *MemoryCacheStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := &memoryCacheEntry{key: key, value: value, expires: expiry(ttl)}
+	if elem, ok := s.entries[key]; ok {
+		elem.Value = entry
+		s.order.MoveToFront(elem)
+		return nil
+	}
+	s.entries[key] = s.order.PushFront(entry)
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+	return nil
+}
+
+func (s *MemoryCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// DirCacheStore keeps one file per entry in a directory, so the cache
+// survives restarts. File modification times track use; beyond MaxEntries
+// the least recently used files are removed.
+type DirCacheStore struct {
+	// MaxEntries bounds the number of files; 0 means unbounded
+	MaxEntries int
+
+	dir string
+	mu  sync.Mutex
+}
+
+type dirCacheEntry struct {
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires"`
+	Value   []byte    `json:"value"`
+}
+
+// NewDirCacheStore stores entries in dir, creating it when missing
+func NewDirCacheStore(dir string) (*DirCacheStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirCacheStore{dir: dir}, nil
+}
+
+func (s *DirCacheStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *DirCacheStore) Get(key string) ([]byte, bool) {
+	path := s.path(key)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	entry := dirCacheEntry{}
+	if err := json.Unmarshal(raw, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+	if expired(entry.Expires) {
+		os.Remove(path)
+		return nil, false
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return entry.Value, true
+}
+
+func (s *DirCacheStore) Set(key string, value []byte, ttl time.Duration) error {
+	raw, err := json.Marshal(dirCacheEntry{Key: key, Expires: expiry(ttl), Value: value})
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(s.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(raw)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return s.evict()
+}
+
+// evict removes the least recently used files beyond MaxEntries
+func (s *DirCacheStore) evict() error {
+	if s.MaxEntries <= 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil || len(files) <= s.MaxEntries {
+		return err
+	}
+	used := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			used[file] = info.ModTime()
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return used[files[i]].Before(used[files[j]]) })
+	for _, file := range files[:len(files)-s.MaxEntries] {
+		os.Remove(file)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package solanarpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCache(t *testing.T) {
+	posts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		posts++
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":1574721591,"id":%d}`, rpcReq.ID)
+	}))
+	defer srv.Close()
+
+	cache := NewResponseCache(nil)
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL}, WithCache(cache))
+	assert.NoError(t, err)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		resp, err := client.GetBlockTime(ctx, 5)
+		assert.NoError(t, err)
+		blockTime, err := ParseBlockTimeResponse(resp)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1574721591), blockTime)
+	}
+	assert.Equal(t, 1, posts)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, cache.Stats())
+
+	// params are compared in canonical form
+	_, err = client.DoPostRequest(ctx, NewRPCRequest("getConfirmedBlock", 7, map[string]string{"encoding": "json", "commitment": "finalized"}))
+	assert.NoError(t, err)
+	_, err = client.DoPostRequest(ctx, NewRPCRequest("getConfirmedBlock", 7, ConfirmedBlockParamObj{Encoding: "json", Commitment: Finalized}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, posts)
+
+	// below finalized, the newest signature page and other methods go through
+	client.DoPostRequest(ctx, NewRPCRequest("getConfirmedBlock", 7, map[string]string{"commitment": "confirmed"}))
+	client.DoPostRequest(ctx, NewRPCRequest("getConfirmedBlock", 7, map[string]string{"commitment": "confirmed"}))
+	client.GetConfirmedSignaturesForAddress2(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri", nil)
+	client.GetConfirmedSignaturesForAddress2(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri", nil)
+	client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Equal(t, 8, posts)
+
+	extra := &ConfirmedSignaturesForAddress2ParamExtra{Before: "5h6xBEauJ3PK6SWCZ1PGjBvj8vDdWG3KpwATGy1ARAXFSDwt8GFXM7W5Ncn16wmqokgpiKRLuS83KUxyZyv2sUYv"}
+	client.GetConfirmedSignaturesForAddress2(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri", extra)
+	client.GetConfirmedSignaturesForAddress2(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri", extra)
+	assert.Equal(t, 9, posts)
+
+	// slots past 2^53 keep apart
+	client.GetBlockTime(ctx, 1<<53)
+	client.GetBlockTime(ctx, 1<<53+1)
+	assert.Equal(t, 11, posts)
+}
+
+func TestMemoryCacheStore(t *testing.T) {
+	store := NewMemoryCacheStore(2)
+	store.Set("a", []byte("1"), 0)
+	store.Set("b", []byte("2"), 0)
+	store.Get("a")
+	store.Set("c", []byte("3"), 0)
+	_, ok := store.Get("b")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	value, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, store.Len())
+
+	store.Set("d", []byte("4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, ok = store.Get("d")
+	assert.False(t, ok, "expired entry should be gone")
+}
+
+func TestDirCacheStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDirCacheStore(dir)
+	assert.NoError(t, err)
+	store.MaxEntries = 2
+	assert.NoError(t, store.Set("a", []byte("1"), 0))
+	assert.NoError(t, store.Set("b", []byte("2"), time.Hour))
+
+	reopened, err := NewDirCacheStore(dir)
+	assert.NoError(t, err)
+	value, ok := reopened.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("2"), value)
+
+	past := time.Now().Add(-time.Hour)
+	assert.NoError(t, os.Chtimes(store.path("a"), past, past))
+	assert.NoError(t, store.Set("c", []byte("3"), 0))
+	_, ok = store.Get("a")
+	assert.False(t, ok, "least recently used file should be removed")
+
+	assert.NoError(t, store.Set("e", []byte("5"), time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	_, ok = store.Get("e")
+	assert.False(t, ok)
+}
@@ -0,0 +1,50 @@
+package solanarpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"solanarpc"
+	"solanarpc/internal/fixtures"
+	"solanarpc/solanarpctest"
+)
+
+func TestCall(t *testing.T) {
+	server := solanarpctest.NewServer()
+	defer server.Close()
+	methods := []string{}
+	client := server.Client(solanarpc.WithMiddleware(func(next solanarpc.Handler) solanarpc.Handler {
+		return func(ctx context.Context, rpcReq *solanarpc.RPCRequest) (*solanarpc.RPCResponse, error) {
+			methods = append(methods, rpcReq.Method)
+			return next(ctx, rpcReq)
+		}
+	}))
+	ctx := context.Background()
+
+	// {context, value} results are unwrapped unless the context is asked for
+	var balance uint64
+	assert.NoError(t, client.Call(ctx, "getBalance", []interface{}{"83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri"}, &balance))
+	assert.Equal(t, uint64(27020041285), balance)
+	supply := new(solanarpc.TokenValue)
+	result := &solanarpc.ContextResult{Value: supply}
+	assert.NoError(t, client.Call(ctx, "getTokenSupply", []interface{}{"3wyAj7Rt1TWVPZVteFJPLa26JmLvdb1CAKEFZm3NY75E"}, result))
+	assert.NotZero(t, result.Context.Slot)
+	assert.NotEmpty(t, supply.Amount)
+
+	server.HandleResult("getSlot", 42)
+	var slot uint64
+	assert.NoError(t, client.Call(ctx, "getSlot", nil, &slot))
+	assert.Equal(t, uint64(42), slot)
+	assert.Equal(t, []string{"getBalance", "getTokenSupply", "getSlot"}, methods)
+
+	server.HandleFixture("getAccountInfo", fixtures.AccountNotExist)
+	account := new(solanarpc.AccountInfoValue)
+	assert.Equal(t, solanarpc.ErrNullResult, client.Call(ctx, "getAccountInfo", []interface{}{"CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12"}, account))
+	assert.NoError(t, client.Call(ctx, "getAccountInfo", []interface{}{"CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12"}, nil))
+
+	assert.True(t, errors.Is(client.Call(ctx, "getNoSuchThing", nil, nil), solanarpc.ErrMethodNotFound))
+	server.HandleRaw("getSlot", fixtures.Balance01)
+	assert.Equal(t, solanarpc.ErrIDMismatch, client.Call(ctx, "getSlot", nil, &slot))
+}
@@ -0,0 +1,237 @@
+package solanarpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Cassette holds recorded JSON-RPC exchanges. Request and response bodies
+// are kept as sent, single or batch.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request    json.RawMessage `json:"request"`
+	StatusCode int             `json:"status"`
+	Response   json.RawMessage `json:"response"`
+}
+
+// LoadCassette reads a cassette file written by RecordTransport.Save
+func LoadCassette(path string) (*Cassette, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(raw, cassette); err != nil {
+		return nil, err
+	}
+	return cassette, nil
+}
+
+func (c *Cassette) Save(path string) error {
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(raw, '\n'), 0o644)
+}
+
+// RecordTransport passes requests on to Transport and keeps every exchange
+// in Cassette. Use it as the Transport of an RPCClient http.Client against a
+// real node, then Save the cassette next to the test.
+type RecordTransport struct {
+	// Transport sends the requests; nil means http.DefaultTransport
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	// a RoundTripper must not change req, the body goes on in a clone
+	sent := req.Clone(req.Context())
+	sent.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	if json.Valid(reqBody) && json.Valid(respBody) {
+		t.mu.Lock()
+		t.cassette.Interactions = append(t.cassette.Interactions, Interaction{Request: reqBody, StatusCode: resp.StatusCode, Response: respBody})
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// Cassette returns a copy of what was recorded so far
+func (t *RecordTransport) Cassette() *Cassette {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), t.cassette.Interactions...)}
+}
+
+func (t *RecordTransport) Save(path string) error {
+	return t.Cassette().Save(path)
+}
+
+// ReplayTransport answers requests from a Cassette without any network.
+// A request matches an interaction with the same methods and params, IDs
+// aside; the recorded response comes back with the IDs of the request.
+// Identical requests get their recorded answers in order, the last one
+// repeating. A request without answer fails with ErrNoRecordedInteraction.
+type ReplayTransport struct {
+	mu      sync.Mutex
+	entries map[string][]Interaction
+	served  map[string]int
+}
+
+func NewReplayTransport(cassette *Cassette) (*ReplayTransport, error) {
+	t := &ReplayTransport{entries: make(map[string][]Interaction), served: make(map[string]int)}
+	for _, interaction := range cassette.Interactions {
+		key, _, err := interactionKey(interaction.Request)
+		if err != nil {
+			return nil, err
+		}
+		t.entries[key] = append(t.entries[key], interaction)
+	}
+	return t, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	key, ids, err := interactionKey(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	recorded := t.entries[key]
+	n := t.served[key]
+	if len(recorded) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrNoRecordedInteraction, key)
+	}
+	if n < len(recorded)-1 {
+		t.served[key] = n + 1
+	} else {
+		n = len(recorded) - 1
+	}
+	t.mu.Unlock()
+
+	interaction := recorded[n]
+	_, recordedIDs, _ := interactionKey(interaction.Request)
+	body, err := replaceIDs(interaction.Response, recordedIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	status := interaction.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {HTTPContentType}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// interactionKey returns the methods and canonical params of a request body,
+// and the IDs it carries in order
+func interactionKey(body []byte) (string, []json.RawMessage, error) {
+	reqs := []struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}{}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] != '[' {
+		trimmed = []byte("[" + string(trimmed) + "]")
+	}
+	if err := json.Unmarshal(trimmed, &reqs); err != nil {
+		return "", nil, err
+	}
+	parts := make([]string, len(reqs))
+	ids := make([]json.RawMessage, len(reqs))
+	for i, rpcReq := range reqs {
+		params := []byte("[]")
+		if len(rpcReq.Params) > 0 && string(rpcReq.Params) != "null" {
+			var err error
+			if params, err = canonicalJSON(rpcReq.Params); err != nil {
+				return "", nil, err
+			}
+		}
+		parts[i] = rpcReq.Method + string(params)
+		ids[i] = rpcReq.ID
+	}
+	return strings.Join(parts, ","), ids, nil
+}
+
+// replaceIDs rewrites the response IDs recorded as from[i] into to[i]
+func replaceIDs(response json.RawMessage, from, to []json.RawMessage) ([]byte, error) {
+	mapping := make(map[string]json.RawMessage, len(from))
+	for i := range from {
+		if i < len(to) {
+			mapping[string(from[i])] = to[i]
+		}
+	}
+	rewrite := func(msg map[string]json.RawMessage) {
+		if id, ok := mapping[string(msg["id"])]; ok {
+			msg["id"] = id
+		}
+	}
+	trimmed := bytes.TrimSpace(response)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		msgs := []map[string]json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &msgs); err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			rewrite(msg)
+		}
+		return json.Marshal(msgs)
+	}
+	msg := map[string]json.RawMessage{}
+	if err := json.Unmarshal(trimmed, &msg); err != nil {
+		return nil, err
+	}
+	rewrite(msg)
+	return json.Marshal(msg)
+}
+
+// readBody reads and closes body
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
@@ -0,0 +1,76 @@
+package solanarpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"solanarpc/internal/fixtures"
+)
+
+func TestRecordAndReplayTransport(t *testing.T) {
+	// the node answers with the recorded fixtures, under the caller's id
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		answer := fixtures.Balance01
+		if rpcReq.Method == "getBlockTime" {
+			answer = fixtures.BlockTime01
+		}
+		w.Write([]byte(strings.Replace(answer, `"id": 1`, `"id": `+strconv.FormatUint(rpcReq.ID, 10), 1)))
+	}))
+	defer srv.Close()
+
+	recorder := &RecordTransport{}
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL}, WithHTTPClient(&http.Client{Transport: recorder}))
+	assert.NoError(t, err)
+	ctx := context.Background()
+	_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.NoError(t, err)
+	_, err = client.GetBlockTime(ctx, 5)
+	assert.NoError(t, err)
+	_, err = client.GetBlockTime(ctx, 1<<53)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	assert.NoError(t, recorder.Save(path))
+	srv.Close()
+
+	cassette, err := LoadCassette(path)
+	assert.NoError(t, err)
+	assert.Len(t, cassette.Interactions, 3)
+	replay, err := NewReplayTransport(cassette)
+	assert.NoError(t, err)
+	client, err = NewClient(SolanaEndpoint{Host: srv.URL}, WithHTTPClient(&http.Client{Transport: replay}), WithRetryPolicy(fastRetryPolicy()))
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+		assert.NoError(t, err)
+		balance, _, err := ParseBalanceResponse(resp)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(27020041285), balance)
+	}
+	resp, err := client.GetBlockTime(ctx, 5)
+	assert.NoError(t, err)
+	blockTime, err := ParseBlockTimeResponse(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1574721591), blockTime)
+
+	_, err = client.GetBlockTime(ctx, 6)
+	assert.True(t, errors.Is(err, ErrNoRecordedInteraction), "got %v", err)
+	assert.Contains(t, err.Error(), "getBlockTime[6]")
+	assert.False(t, IsRetryable(err))
+
+	// slots past 2^53 keep apart
+	_, err = client.GetBlockTime(ctx, 1<<53)
+	assert.NoError(t, err)
+	_, err = client.GetBlockTime(ctx, 1<<53+1)
+	assert.True(t, errors.Is(err, ErrNoRecordedInteraction), "got %v", err)
+}
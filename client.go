@@ -0,0 +1,245 @@
+package solanarpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Cluster holds the public RPC and PubSub URLs of a Solana cluster. It is an
+// Endpoint, so a preset can be passed to NewClient directly.
+type Cluster struct {
+	Name string
+	RPC  string
+	WS   string
+}
+
+func (c Cluster) DialAddress() (string, error) {
+	if len(c.RPC) == 0 {
+		return "", ErrInvalidHost
+	}
+	return c.RPC, nil
+}
+
+var (
+	MainnetBeta = Cluster{Name: "mainnet-beta", RPC: "https://api.mainnet-beta.solana.com", WS: "wss://api.mainnet-beta.solana.com"}
+	Devnet      = Cluster{Name: "devnet", RPC: "https://api.devnet.solana.com", WS: "wss://api.devnet.solana.com"}
+	Testnet     = Cluster{Name: "testnet", RPC: "https://api.testnet.solana.com", WS: "wss://api.testnet.solana.com"}
+	// Localhost is a solana-test-validator with its default ports
+	Localhost = Cluster{Name: "localhost", RPC: "http://127.0.0.1:" + strconv.Itoa(HttpPort), WS: "ws://127.0.0.1:" + strconv.Itoa(WebsocketPort)}
+)
+
+// Option configures an RPCClient built by NewClient
+type Option func(*RPCClient)
+
+// NewClient builds a client for endpoint, e.g. NewClient(Devnet) or
+// NewClient(SolanaEndpoint{Host: url}, WithTimeout(10*time.Second))
+func NewClient(endpoint Endpoint, opts ...Option) (*RPCClient, error) {
+	if endpoint == nil {
+		return nil, ErrInvalidHost
+	}
+	if _, err := endpoint.DialAddress(); err != nil {
+		return nil, ErrInvalidHost
+	}
+	r := &RPCClient{Endpoint: endpoint, Timeout: RequestTimeout}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.Client == nil {
+		// the per request Timeout applies instead of http.Client.Timeout
+		r.Client = new(http.Client)
+	}
+	return r, nil
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *RPCClient) {
+		r.Client = client
+	}
+}
+
+// WithTimeout bounds each HTTP round trip, body read included
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *RPCClient) {
+		r.Timeout = timeout
+	}
+}
+
+// WithCommitment sets the commitment of requests that do not set one
+func WithCommitment(commitment CommitmentVal) Option {
+	return func(r *RPCClient) {
+		r.Commitment = commitment
+	}
+}
+
+// WithEncoding sets the encoding of requests that accept one and do not set it
+func WithEncoding(encoding EncodeMethod) Option {
+	return func(r *RPCClient) {
+		r.Encoding = encoding
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(r *RPCClient) {
+		r.UserAgent = userAgent
+	}
+}
+
+// WithHTTPHeader adds a header to every request
+func WithHTTPHeader(key, value string) Option {
+	return func(r *RPCClient) {
+		if r.Header == nil {
+			r.Header = http.Header{}
+		}
+		r.Header.Add(key, value)
+	}
+}
+
+func WithLogger(logger Logger) Option {
+	return func(r *RPCClient) {
+		r.Logger = logger
+	}
+}
+
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(r *RPCClient) {
+		r.Retry = policy
+	}
+}
+
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(r *RPCClient) {
+		r.RateLimit = limiter
+	}
+}
+
+func WithMetrics(metrics Metrics) Option {
+	return func(r *RPCClient) {
+		r.Metrics = metrics
+	}
+}
+
+func WithTracer(tracer Tracer) Option {
+	return func(r *RPCClient) {
+		r.Tracer = tracer
+	}
+}
+
+func WithCache(cache *ResponseCache) Option {
+	return func(r *RPCClient) {
+		r.Cache = cache
+	}
+}
+
+func WithMiddleware(mws ...Middleware) Option {
+	return func(r *RPCClient) {
+		r.Use(mws...)
+	}
+}
+
+// configIndex is the position of the config object of methods taking one,
+// after their positional params. -1 marks a config that follows optional
+// positional params, which is found by being the last param and an object.
+var configIndex = map[string]int{
+	"getAccountInfo":                    1,
+	"getBalance":                        1,
+	"getBlockHeight":                    0,
+	"getBlockProduction":                0,
+	"getConfirmedBlock":                 1,
+	"getConfirmedBlocks":                -1,
+	"getConfirmedBlocksWithLimit":       2,
+	"getConfirmedSignaturesForAddress2": 1,
+	"getConfirmedTransaction":           1,
+	"getEpochInfo":                      0,
+	"getMultipleAccounts":               1,
+	"getProgramAccounts":                1,
+	"getSlot":                           0,
+	"getTokenAccountBalance":            1,
+	"getTokenAccountsByDelegate":        2,
+	"getTokenAccountsByOwner":           2,
+	"getTokenSupply":                    1,
+	"getTransaction":                    1,
+}
+
+// encodingMethods accept an encoding in their config object
+var encodingMethods = map[string]bool{
+	"getAccountInfo":             true,
+	"getConfirmedBlock":          true,
+	"getConfirmedTransaction":    true,
+	"getMultipleAccounts":        true,
+	"getProgramAccounts":         true,
+	"getTokenAccountsByDelegate": true,
+	"getTokenAccountsByOwner":    true,
+	"getTransaction":             true,
+}
+
+// applyDefaults fills the client Commitment and Encoding into the config
+// object of rpcReq where it leaves them empty. Params are copied, never
+// changed in place.
+func (r *RPCClient) applyDefaults(rpcReq *RPCRequest) {
+	if len(r.Commitment) == 0 && len(r.Encoding) == 0 {
+		return
+	}
+	idx, ok := configIndex[rpcReq.Method]
+	if !ok {
+		return
+	}
+	params := rpcReq.Params
+	if idx < 0 {
+		idx = len(params)
+		if idx > 0 && asObject(params[idx-1]) != nil {
+			idx--
+		}
+	}
+	if len(params) < idx {
+		return
+	}
+
+	config := map[string]interface{}{}
+	if len(params) > idx {
+		if config = asObject(params[idx]); config == nil {
+			return
+		}
+	}
+	changed := false
+	if len(r.Commitment) > 0 && isEmptyParam(config["commitment"]) {
+		config["commitment"] = r.Commitment
+		changed = true
+	}
+	if len(r.Encoding) > 0 && encodingMethods[rpcReq.Method] && isEmptyParam(config["encoding"]) {
+		config["encoding"] = r.Encoding
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	newParams := make([]interface{}, len(params))
+	copy(newParams, params)
+	if len(newParams) == idx {
+		newParams = append(newParams, nil)
+	}
+	newParams[idx] = config
+	rpcReq.Params = newParams
+}
+
+// asObject returns param as a JSON object, or nil when it does not encode to one
+func asObject(param interface{}) map[string]interface{} {
+	raw, err := json.Marshal(param)
+	if err != nil || len(raw) == 0 || raw[0] != '{' {
+		return nil
+	}
+	object := map[string]interface{}{}
+	if err := unmarshalNumbers(raw, &object); err != nil {
+		return nil
+	}
+	return object
+}
+
+func isEmptyParam(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	s, ok := value.(string)
+	return ok && len(s) == 0
+}
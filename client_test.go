@@ -0,0 +1,96 @@
+package solanarpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	var got RPCRequest
+	var gotHeader http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotHeader = req.Header
+		got = RPCRequest{}
+		decodeRequest(req, &got)
+		if got.Method == "getSlot" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", HTTPContentType)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":null,"id":%d}`, got.ID)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL},
+		WithCommitment(Confirmed),
+		WithEncoding(Base64),
+		WithUserAgent("indexer/1.0"),
+		WithHTTPHeader("X-Api-Key", "secret"),
+		WithTimeout(50*time.Millisecond))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.GetAccountInfo(ctx, "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "indexer/1.0", gotHeader.Get("User-Agent"))
+	assert.Equal(t, "secret", gotHeader.Get("X-Api-Key"))
+	assert.Equal(t, []interface{}{"CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", map[string]interface{}{"commitment": "confirmed", "encoding": "base64"}}, got.Params)
+
+	// an explicit commitment wins, and methods without encoding do not get one
+	_, err = client.GetConfirmedBlocks(ctx, &ConfirmedBlocksParam{StartSlot: 5, EndSlot: 10, CommitmentConfig: CommitmentConfig{Commitment: Finalized}})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{5.0, 10.0, map[string]interface{}{"commitment": "finalized"}}, got.Params)
+	_, err = client.GetConfirmedBlocks(ctx, &ConfirmedBlocksParam{StartSlot: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{5.0, map[string]interface{}{"commitment": "confirmed"}}, got.Params)
+
+	// the client timeout is a retryable transport error, not the caller's deadline
+	_, err = client.DoPostRequest(ctx, NewRPCRequest("getSlot"))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, ErrRequestTimeout, err)
+	assert.True(t, IsRetryable(err))
+}
+
+func TestApplyDefaultsKeepsLargeNumbers(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ = ioutil.ReadAll(req.Body)
+		rpcReq := RPCRequest{}
+		json.Unmarshal(body, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":null,"id":%d}`, rpcReq.ID)
+	}))
+	defer srv.Close()
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL}, WithCommitment(Finalized))
+	assert.NoError(t, err)
+
+	extra := &AccountInfoExtraParams{Encoding: "base64"}
+	extra.DataSlice.Offset = 1<<53 + 1
+	_, err = client.GetAccountInfo(context.Background(), "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", extra)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"offset":9007199254740993`)
+	assert.Contains(t, string(body), `"commitment":"finalized"`)
+}
+
+func TestNewClientPresets(t *testing.T) {
+	client, err := NewClient(Devnet)
+	assert.NoError(t, err)
+	url, err := client.HttpRequstURL("")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.devnet.solana.com", url)
+	assert.Equal(t, "ws://127.0.0.1:8900", Localhost.WS)
+
+	_, err = NewClient(Cluster{})
+	assert.Equal(t, ErrInvalidHost, err)
+	_, err = NewClient(nil)
+	assert.Equal(t, ErrInvalidHost, err)
+}
@@ -0,0 +1,282 @@
+// Command genapi generates the request wrappers of RPCClient and their
+// parsers from a declarative method spec. It is run by go generate in the
+// package directory:
+//
+//	go run ./cmd/genapi -spec methods.json -api api_gen.go -parse queryParse_gen.go
+//
+// Each spec entry names the Go method, its positional params, an optional
+// config object appended last when set, and the result type. A string param
+// must not be empty. A result with context is a {context, value} result whose
+// parser also returns the RPCContext. A null result or value is the null
+// error of the spec, ErrNullResult by default, unless the result is nullable:
+// then a null result parses to the zero value without error. Methods without
+// a result get no parser. A fallback method is sent when the node answers that it does not
+// know the method, e.g. the deprecated name on an older node.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Method is one entry of the spec
+type Method struct {
+	Name     string  `json:"name"`
+	Method   string  `json:"method,omitempty"`
+	Fallback string  `json:"fallback,omitempty"`
+	Doc      string  `json:"doc,omitempty"`
+	Params   []Param `json:"params,omitempty"`
+	Config   *Param  `json:"config,omitempty"`
+	Result   *Result `json:"result,omitempty"`
+	Parser   string  `json:"parser,omitempty"`
+}
+
+// Param is a positional param, or the config object whose type is given
+// without the pointer
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Result is the Go type the result is decoded into
+type Result struct {
+	Type     string `json:"type"`
+	Context  bool   `json:"context,omitempty"`
+	Null     string `json:"null,omitempty"`
+	Nullable bool   `json:"nullable,omitempty"`
+}
+
+// Decl declares value, the variable the result is decoded into
+func (r Result) Decl() string {
+	if strings.HasPrefix(r.Type, "*") {
+		return "value := new(" + r.Type[1:] + ")"
+	}
+	return "var value " + r.Type
+}
+
+// Target is what json.Unmarshal decodes into
+func (r Result) Target() string {
+	if strings.HasPrefix(r.Type, "*") {
+		return "value"
+	}
+	return "&value"
+}
+
+// Zero is the value returned along an error
+func (r Result) Zero() string {
+	switch {
+	case strings.HasPrefix(r.Type, "*"), strings.HasPrefix(r.Type, "[]"), strings.HasPrefix(r.Type, "map["), r.Type == "interface{}":
+		return "nil"
+	case r.Type == "string":
+		return `""`
+	case r.Type == "bool":
+		return "false"
+	case strings.HasPrefix(r.Type, "int"), strings.HasPrefix(r.Type, "uint"), strings.HasPrefix(r.Type, "float"):
+		return "0"
+	}
+	return r.Type + "{}"
+}
+
+// NullErr is the error of a null result
+func (r Result) NullErr() string {
+	if len(r.Null) == 0 {
+		return "ErrNullResult"
+	}
+	return r.Null
+}
+
+// LoadSpec reads a spec and fills in the RPC method and parser names left out
+func LoadSpec(path string) ([]Method, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	methods := []Method{}
+	if err := json.Unmarshal(raw, &methods); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for i := range methods {
+		m := &methods[i]
+		if len(m.Name) == 0 {
+			return nil, fmt.Errorf("method %d: no name", i)
+		}
+		if seen[m.Name] {
+			return nil, fmt.Errorf("%s: defined twice", m.Name)
+		}
+		seen[m.Name] = true
+		for _, p := range append(append([]Param(nil), m.Params...), configParams(m)...) {
+			if len(p.Name) == 0 || len(p.Type) == 0 {
+				return nil, fmt.Errorf("%s: param without name or type", m.Name)
+			}
+		}
+		if m.Result != nil && len(m.Result.Type) == 0 {
+			return nil, fmt.Errorf("%s: result without type", m.Name)
+		}
+		if m.Result != nil && m.Result.Nullable && (m.Result.Context || len(m.Result.Null) > 0) {
+			return nil, fmt.Errorf("%s: a nullable result has no context or null error", m.Name)
+		}
+		if len(m.Method) == 0 {
+			m.Method = lowerFirst(m.Name)
+		}
+		if len(m.Parser) == 0 {
+			m.Parser = "Parse" + strings.TrimPrefix(m.Name, "Get") + "Response"
+		}
+	}
+	return methods, nil
+}
+
+func configParams(m *Method) []Param {
+	if m.Config == nil {
+		return nil
+	}
+	return []Param{*m.Config}
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+const header = "// Code generated by cmd/genapi from methods.json. DO NOT EDIT.\n\npackage solanarpc\n"
+
+var apiTemplate = template.Must(template.New("api").Parse(header + `
+import "context"
+{{range .}}
+// {{.Name}} sends {{.Method}}{{if .Result}}, whose response {{.Parser}} parses{{end}}.{{with .Fallback}} A node that does not know it is sent {{.}} instead.{{end}}{{with .Doc}} {{.}}{{end}}
+func (r *RPCClient) {{.Name}}(ctx context.Context{{range .Params}}, {{.Name}} {{.Type}}{{end}}{{with .Config}}, {{.Name}} *{{.Type}}{{end}}) (*RPCResponse, error) {
+{{- range .Params}}{{if eq .Type "string"}}
+	if len({{.Name}}) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+{{- end}}{{end}}
+	id := RandomID()
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: "{{.Method}}"}
+{{- if .Params}}
+	rpcReq.Params = append(rpcReq.Params{{range .Params}}, {{.Name}}{{end}})
+{{- end}}
+{{- with .Config}}
+	if {{.Name}} != nil && !isEmptyConfig(*{{.Name}}) {
+		rpcReq.Params = append(rpcReq.Params, *{{.Name}})
+	}
+{{- end}}
+	resp, err := r.DoPostRequest(ctx, rpcReq)
+{{- with .Fallback}}
+	if err == nil && resp.ID == id && resp.Error.Code == ErrCodeMethodNotFound {
+		id = RandomID()
+		rpcReq.ID, rpcReq.Method = id, "{{.}}"
+		resp, err = r.DoPostRequest(ctx, rpcReq)
+	}
+{{- end}}
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "{{.Name}}"}).Error(err)
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, ErrIDMismatch
+	}
+	return resp, nil
+}
+{{end}}`))
+
+var parseTemplate = template.Must(template.New("parse").Parse(header + `
+{{if .Plain}}import "encoding/json"{{end}}
+{{range .Methods}}{{if .Result}}{{if .Result.Context}}
+// {{.Parser}} parses the response of {{.Name}} and returns its context too
+func {{.Parser}}(resp *RPCResponse) ({{.Result.Type}}, RPCContext, error) {
+	{{.Result.Decl}}
+	rpcCtx, err := ParseContextResult(resp, {{.Result.Target}})
+{{- if .Result.Null}}
+	if err == ErrNullResult {
+		err = {{.Result.Null}}
+	}
+{{- end}}
+	if err != nil {
+		parseLog().WithFields(Fields{"func": "{{.Parser}}"}).Error(err)
+		return {{.Result.Zero}}, rpcCtx, err
+	}
+	return value, rpcCtx, nil
+}
+{{else}}
+// {{.Parser}} parses the response of {{.Name}}
+func {{.Parser}}(resp *RPCResponse) ({{.Result.Type}}, error) {
+	if resp.Error.Code != 0 {
+		parseLog().WithFields(Fields{"func": "{{.Parser}}"}).Error(resp.Error.Err())
+		return {{.Result.Zero}}, resp.Error.Err()
+	}
+{{- if not .Result.Nullable}}
+	if isNullJSON(resp.Result) {
+		parseLog().WithFields(Fields{"func": "{{.Parser}}"}).Error({{.Result.NullErr}})
+		return {{.Result.Zero}}, {{.Result.NullErr}}
+	}
+{{- end}}
+	{{.Result.Decl}}
+	if err := json.Unmarshal(resp.Result, {{.Result.Target}}); err != nil {
+		parseLog().WithFields(Fields{"func": "{{.Parser}}"}).Error(err)
+		return {{.Result.Zero}}, err
+	}
+	return value, nil
+}
+{{end}}{{end}}{{end}}`))
+
+// Generate returns the sources of the wrappers and of the parsers of methods
+func Generate(methods []Method) (api []byte, parse []byte, err error) {
+	if api, err = render(apiTemplate, methods); err != nil {
+		return nil, nil, err
+	}
+	plain := false
+	for _, m := range methods {
+		if m.Result != nil && !m.Result.Context {
+			plain = true
+		}
+	}
+	parse, err = render(parseTemplate, struct {
+		Methods []Method
+		Plain   bool
+	}{methods, plain})
+	return api, parse, err
+}
+
+func render(tmpl *template.Template, data interface{}) ([]byte, error) {
+	buf := bytes.Buffer{}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.New(tmpl.Name() + ": " + err.Error())
+	}
+	return src, nil
+}
+
+func main() {
+	specPath := flag.String("spec", "methods.json", "method spec")
+	apiPath := flag.String("api", "api_gen.go", "output of the request wrappers")
+	parsePath := flag.String("parse", "queryParse_gen.go", "output of the parsers")
+	flag.Parse()
+
+	methods, err := LoadSpec(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	api, parse, err := Generate(methods)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*apiPath, api, 0644); err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*parsePath, parse, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
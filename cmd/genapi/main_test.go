@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	methods, err := LoadSpec(filepath.Join("..", "..", "methods.json"))
+	assert.NoError(t, err)
+	api, parse, err := Generate(methods)
+	assert.NoError(t, err)
+
+	for name, want := range map[string][]byte{"api_gen.go": api, "queryParse_gen.go": parse} {
+		got, err := ioutil.ReadFile(filepath.Join("..", "..", name))
+		assert.NoError(t, err)
+		assert.Equal(t, string(want), string(got), "%s is stale, run go generate", name)
+	}
+}
+
+func TestLoadSpec(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "methods.json")
+	spec := `[{"name": "GetSlot", "config": {"name": "commitment", "type": "CommitmentConfig"}, "result": {"type": "uint64"}},
+	{"name": "GetTokenLargestAccounts", "method": "getTokenLargestAccounts", "params": [{"name": "mint", "type": "string"}], "result": {"type": "[]TokenValue", "context": true}, "parser": "ParseTokenLargestAccounts"},
+	{"name": "GetSlotLeaders", "result": {"type": "[]string", "nullable": true}}]`
+	assert.NoError(t, ioutil.WriteFile(path, []byte(spec), 0644))
+	methods, err := LoadSpec(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "getSlot", methods[0].Method)
+	assert.Equal(t, "ParseSlotResponse", methods[0].Parser)
+	assert.Equal(t, "ParseTokenLargestAccounts", methods[1].Parser)
+	assert.Equal(t, "0", methods[0].Result.Zero())
+	assert.Equal(t, "nil", methods[1].Result.Zero())
+
+	api, parse, err := Generate(methods)
+	assert.NoError(t, err)
+	assert.Contains(t, string(api), `if commitment != nil && !isEmptyConfig(*commitment) {`)
+	assert.Contains(t, string(api), `r.logger().WithFields(Fields{"func": "GetTokenLargestAccounts"}).Error(err)`)
+	assert.Contains(t, string(parse), `func ParseTokenLargestAccounts(resp *RPCResponse) ([]TokenValue, RPCContext, error) {`)
+	assert.Contains(t, string(parse), `return 0, ErrNullResult`)
+	leaders := string(parse[strings.Index(string(parse), "func ParseSlotLeadersResponse"):])
+	assert.NotContains(t, leaders, `isNullJSON`)
+
+	for _, bad := range []string{`[{"params": []}]`, `[{"name": "GetSlot"}, {"name": "GetSlot"}]`, `[{"name": "GetSlot", "params": [{"name": "x"}]}]`, `[{"name": "GetSlot", "result": {}}]`, `[{"name": "GetSlot", "result": {"type": "uint64", "nullable": true, "null": "ErrUnknownBlock"}}]`} {
+		assert.NoError(t, ioutil.WriteFile(path, []byte(bad), 0644))
+		_, err := LoadSpec(path)
+		assert.Error(t, err, bad)
+	}
+}
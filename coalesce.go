@@ -0,0 +1,130 @@
+package solanarpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DefaultCoalescedMethods are coalesced by a CoalesceMiddleware created
+// without methods. They are read-only and often polled by many callers.
+var DefaultCoalescedMethods = []string{
+	"getAccountInfo",
+	"getBalance",
+	"getBlockHeight",
+	"getEpochInfo",
+	"getMultipleAccounts",
+	"getSlot",
+	"getTokenAccountBalance",
+	"getTokenSupply",
+}
+
+// inflightCall is one request shared by every caller asking the same
+type inflightCall struct {
+	done    chan struct{}
+	resp    *RPCResponse
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// CoalesceMiddleware collapses concurrent requests for the same method and
+// params into one call, whose response is handed to every caller with its
+// own request ID. A caller whose ctx is done stops waiting without affecting
+// the others; the shared call is canceled once no caller is left.
+// NonIdempotentMethods are never coalesced. Add it before the other
+// middlewares so callers also share retries and rate limit slots.
+//
+// Requests match on method, params and the headers set with WithHeader. What
+// is decided further down the chain, such as the endpoint host, the default
+// commitment or headers added by later middlewares, is not part of the match:
+// every caller gets what the first caller's request turned into.
+func CoalesceMiddleware(methods ...string) Middleware {
+	if len(methods) == 0 {
+		methods = DefaultCoalescedMethods
+	}
+	coalesced := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		coalesced[method] = !NonIdempotentMethods[method]
+	}
+	var mu sync.Mutex
+	calls := make(map[string]*inflightCall)
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error) {
+			if !coalesced[rpcReq.Method] {
+				return next(ctx, rpcReq)
+			}
+			params, err := canonicalJSON(rpcReq.Params)
+			if err != nil {
+				return next(ctx, rpcReq)
+			}
+			key := rpcReq.Method + ":" + string(params)
+			if header := headerFromContext(ctx); len(header) > 0 {
+				headerJSON, err := json.Marshal(header)
+				if err != nil {
+					return next(ctx, rpcReq)
+				}
+				key += ":" + string(headerJSON)
+			}
+
+			mu.Lock()
+			call, ok := calls[key]
+			if !ok {
+				callCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+				call = &inflightCall{done: make(chan struct{}), cancel: cancel}
+				calls[key] = call
+				shared := *rpcReq
+				go func() {
+					call.resp, call.err = next(callCtx, &shared)
+					mu.Lock()
+					if calls[key] == call {
+						delete(calls, key)
+					}
+					mu.Unlock()
+					cancel()
+					close(call.done)
+				}()
+			}
+			call.waiters++
+			mu.Unlock()
+
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				mu.Lock()
+				call.waiters--
+				if call.waiters == 0 {
+					if calls[key] == call {
+						delete(calls, key)
+					}
+					call.cancel()
+				}
+				mu.Unlock()
+				return nil, ctx.Err()
+			}
+			if call.err != nil {
+				return nil, call.err
+			}
+			resp := *call.resp
+			resp.ID = rpcReq.ID
+			resp.Result = append([]byte(nil), call.resp.Result...)
+			return &resp, nil
+		}
+	}
+}
+
+// detachedContext keeps the values of parent, such as headers and the trace
+// span, but not its deadline and cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
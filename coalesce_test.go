@@ -0,0 +1,111 @@
+package solanarpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesceMiddleware(t *testing.T) {
+	var posts int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		select {
+		case <-release:
+		case <-req.Context().Done():
+			return
+		}
+		writeResult(w, rpcReq.ID, balanceResult)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL}, WithMiddleware(CoalesceMiddleware()))
+	assert.NoError(t, err)
+
+	// a waiter giving up does not cancel the call of the others
+	canceled, cancel := context.WithCancel(context.Background())
+	canceledErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetBalance(canceled, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+		canceledErr <- err
+	}()
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&posts) == 1 }, time.Second, time.Millisecond)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// GetBalance fails with ErrIDMismatch unless the response carries its ID
+			resp, err := client.GetBalance(context.Background(), "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+			assert.NoError(t, err)
+			assert.JSONEq(t, balanceResult, string(resp.Result), "every caller gets the shared result")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.Equal(t, context.Canceled, <-canceledErr)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&posts))
+
+	// requests with their own headers are not shared
+	release = make(chan struct{})
+	wg.Add(2)
+	for _, key := range []string{"alice", "bob"} {
+		go func(key string) {
+			defer wg.Done()
+			ctx := WithHeader(context.Background(), http.Header{"X-Api-Key": {key}})
+			_, err := client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+			assert.NoError(t, err)
+		}(key)
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&posts) == 3 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// other methods go through one by one
+	client.GetBlockTime(context.Background(), 5)
+	client.GetBlockTime(context.Background(), 5)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&posts))
+}
+
+func TestCoalesceMiddlewareAllWaitersGone(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	// sharedErr receives how the shared call ended
+	sharedErr := make(chan error, 1)
+	observe := func(next Handler) Handler {
+		return func(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error) {
+			resp, err := next(ctx, rpcReq)
+			sharedErr <- ctx.Err()
+			return resp, err
+		}
+	}
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL}, WithMiddleware(CoalesceMiddleware("getSlot"), observe))
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.DoPostRequest(ctx, NewRPCRequest("getSlot"))
+	assert.Equal(t, context.DeadlineExceeded, err)
+	select {
+	case err := <-sharedErr:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Error("shared call should be canceled once no caller waits")
+	}
+}
@@ -1,7 +1,39 @@
 package solanarpc
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	ErrCodeParseError     = -32700
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternalError  = -32603
+)
+
+// Solana server error codes
+const (
+	ErrCodeBlockCleanedUp                           = -32001
+	ErrCodeSendTransactionPreflightFailure          = -32002
+	ErrCodeTransactionSignatureVerificationFailure  = -32003
+	ErrCodeBlockNotAvailable                        = -32004
+	ErrCodeNodeUnhealthy                            = -32005
+	ErrCodeTransactionPrecompileVerificationFailure = -32006
+	ErrCodeSlotSkipped                              = -32007
+	ErrCodeNoSnapshot                               = -32008
+	ErrCodeLongTermStorageSlotSkipped               = -32009
+	ErrCodeKeyExcludedFromSecondaryIndex            = -32010
+	ErrCodeTransactionHistoryNotAvailable           = -32011
+	ErrCodeScanError                                = -32012
+	ErrCodeTransactionSignatureLenMismatch          = -32013
+	ErrCodeBlockStatusNotAvailableYet               = -32014
+	ErrCodeUnsupportedTransactionVersion            = -32015
+	ErrCodeMinContextSlotNotReached                 = -32016
 )
 
 const (
@@ -21,6 +53,23 @@ const (
 	transactionNotFoundOrConfirmed  = "transaction not found or confirmed"
 	specifiedBlockNotConfirmed      = " specified block is not confirmed"
 	confirmedBlocksParamCanNotBeNil = "ConfirmedBlocksParam can not be nil"
+	batchResponseMissing            = "batch response missing"
+	pubSubClosed                    = "pubsub connection closed"
+	unsubscribeFailed               = "unsubscribe failed"
+	noHealthyEndpoint               = "no healthy endpoint"
+	rateLimited                     = "rate limit exceeded before deadline"
+	methodNotFound                  = "method not found"
+	blockNotAvailable               = "block not available"
+	nodeUnhealthy                   = "node unhealthy"
+	slotSkipped                     = "slot skipped"
+	transactionPreflightFailure     = "transaction preflight failure"
+	transactionHistoryNotAvailable  = "transaction history not available"
+	responseTooLarge                = "response body too large"
+	requestTimeout                  = "request timed out"
+	noRecordedInteraction           = "no recorded interaction"
+	nullResult                      = "null result"
+	transactionNotFound             = "transaction not found"
+	statusCountMismatch             = "signature status count mismatch"
 )
 
 var (
@@ -39,6 +88,23 @@ var (
 	ErrProcessedNotSupported           error
 	ErrSpecifiedBlockNotConfirmed      error
 	ErrConfirmedBlocksParamCanNotBeNil error
+	ErrBatchResponseMissing            error
+	ErrPubSubClosed                    error
+	ErrUnsubscribeFailed               error
+	ErrNoHealthyEndpoint               error
+	ErrRateLimited                     error
+	ErrMethodNotFound                  error
+	ErrBlockNotAvailable               error
+	ErrNodeUnhealthy                   error
+	ErrSlotSkipped                     error
+	ErrTransactionPreflightFailure     error
+	ErrTransactionHistoryNotAvailable  error
+	ErrResponseTooLarge                error
+	ErrRequestTimeout                  error
+	ErrNoRecordedInteraction           error
+	ErrNullResult                      error
+	ErrTransactionNotFound             error
+	ErrStatusCountMismatch             error
 )
 
 func init() {
@@ -57,4 +123,91 @@ func init() {
 	ErrProcessedNotSupported = errors.New(processedNotSupported)
 	ErrSpecifiedBlockNotConfirmed = errors.New(specifiedBlockNotConfirmed)
 	ErrConfirmedBlocksParamCanNotBeNil = errors.New(confirmedBlocksParamCanNotBeNil)
+	ErrBatchResponseMissing = errors.New(batchResponseMissing)
+	ErrPubSubClosed = errors.New(pubSubClosed)
+	ErrUnsubscribeFailed = errors.New(unsubscribeFailed)
+	ErrNoHealthyEndpoint = errors.New(noHealthyEndpoint)
+	ErrRateLimited = errors.New(rateLimited)
+	ErrMethodNotFound = errors.New(methodNotFound)
+	ErrBlockNotAvailable = errors.New(blockNotAvailable)
+	ErrNodeUnhealthy = errors.New(nodeUnhealthy)
+	ErrSlotSkipped = errors.New(slotSkipped)
+	ErrTransactionPreflightFailure = errors.New(transactionPreflightFailure)
+	ErrTransactionHistoryNotAvailable = errors.New(transactionHistoryNotAvailable)
+	ErrResponseTooLarge = errors.New(responseTooLarge)
+	ErrRequestTimeout = errors.New(requestTimeout)
+	ErrNoRecordedInteraction = errors.New(noRecordedInteraction)
+	ErrNullResult = errors.New(nullResult)
+	ErrTransactionNotFound = errors.New(transactionNotFound)
+	ErrStatusCountMismatch = errors.New(statusCountMismatch)
+}
+
+// RPCError is an error object returned by the node. It matches the sentinel
+// errors of its code with errors.Is, e.g. errors.Is(err, ErrNodeUnhealthy).
+type RPCError struct {
+	Code    int
+	Message string
+	Data    json.RawMessage
+}
+
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+func (e *RPCError) Is(target error) bool {
+	for _, sentinel := range rpcErrorSentinels[e.Code] {
+		if target == sentinel {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeData unmarshals the data member of the error into v
+func (e *RPCError) DecodeData(v interface{}) error {
+	if len(e.Data) == 0 {
+		return ErrJSONParseError
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// NumSlotsBehind returns how far an unhealthy node is behind, when it said so
+func (e *RPCError) NumSlotsBehind() (uint64, bool) {
+	data := struct {
+		NumSlotsBehind *uint64 `json:"numSlotsBehind"`
+	}{}
+	if e.Code != ErrCodeNodeUnhealthy || e.DecodeData(&data) != nil || data.NumSlotsBehind == nil {
+		return 0, false
+	}
+	return *data.NumSlotsBehind, true
+}
+
+// rpcErrorSentinels maps error codes to the sentinels an RPCError matches
+var rpcErrorSentinels = map[int][]error{}
+
+func init() {
+	rpcErrorSentinels[ErrCodeMethodNotFound] = []error{ErrMethodNotFound}
+	rpcErrorSentinels[ErrCodeInvalidParams] = []error{ErrInvalidFuncParameter}
+	rpcErrorSentinels[ErrCodeBlockCleanedUp] = []error{ErrBlockNotAvailable, ErrUnknownBlock}
+	rpcErrorSentinels[ErrCodeBlockNotAvailable] = []error{ErrBlockNotAvailable, ErrUnknownBlock}
+	rpcErrorSentinels[ErrCodeNodeUnhealthy] = []error{ErrNodeUnhealthy}
+	rpcErrorSentinels[ErrCodeSlotSkipped] = []error{ErrSlotSkipped, ErrSpecifiedBlockNotConfirmed}
+	rpcErrorSentinels[ErrCodeLongTermStorageSlotSkipped] = []error{ErrSlotSkipped, ErrSpecifiedBlockNotConfirmed}
+	rpcErrorSentinels[ErrCodeSendTransactionPreflightFailure] = []error{ErrTransactionPreflightFailure}
+	rpcErrorSentinels[ErrCodeTransactionHistoryNotAvailable] = []error{ErrTransactionHistoryNotAvailable}
+}
+
+// HTTPError reports a non-2xx HTTP status the node or a proxy in front of it
+// answered with
+type HTTPError struct {
+	StatusCode int
+	RetryAfter time.Duration // from the Retry-After header, 0 when absent
+	Body       string        // at most MaxErrorBodySize bytes of the response body
+}
+
+func (e *HTTPError) Error() string {
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("http status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("http status %d: %s", e.StatusCode, e.Body)
 }
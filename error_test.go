@@ -0,0 +1,30 @@
+package solanarpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCError(t *testing.T) {
+	resp := new(RPCResponse)
+	err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","error":{"code":-32005,"message":"Node is behind by 42 slots","data":{"numSlotsBehind":42}},"id":1}`), resp)
+	assert.NoError(t, err)
+
+	err = fmt.Errorf("balance: %w", resp.Error.Err())
+	assert.True(t, errors.Is(err, ErrNodeUnhealthy))
+	assert.False(t, errors.Is(err, ErrSlotSkipped))
+	rpcErr := new(RPCError)
+	assert.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, ErrCodeNodeUnhealthy, rpcErr.Code)
+	behind, ok := rpcErr.NumSlotsBehind()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), behind)
+	assert.True(t, IsRetryable(err))
+
+	assert.Nil(t, RPCResponseError{}.Err())
+	assert.True(t, errors.Is(RPCResponseError{Code: ErrCodeBlockNotAvailable}.Err(), ErrUnknownBlock))
+}
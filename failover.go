@@ -0,0 +1,171 @@
+package solanarpc
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxFailures      = 3
+	DefaultFailoverCooldown = 30 * time.Second
+)
+
+// EndpointReporter is implemented by endpoints that want to know how each
+// request to the address they handed out went
+type EndpointReporter interface {
+	ReportSuccess(host string)
+	ReportFailure(host string, err error)
+}
+
+// FailoverEndpoint rotates requests through several hosts. A host is taken out
+// of rotation after MaxFailures consecutive failures or a failed health probe,
+// and comes back once Cooldown has passed.
+type FailoverEndpoint struct {
+	MaxFailures int
+	Cooldown    time.Duration
+	// Logger receives host state changes; nil discards them
+	Logger Logger
+
+	mu    sync.Mutex
+	hosts []*failoverHost
+	next  int
+}
+
+type failoverHost struct {
+	host           string
+	failures       int
+	unhealthyUntil time.Time
+}
+
+// HostStatus is a snapshot of one host of a FailoverEndpoint
+type HostStatus struct {
+	Host                string
+	Healthy             bool
+	ConsecutiveFailures int
+	UnhealthyUntil      time.Time
+}
+
+func NewFailoverEndpoint(hosts ...string) (*FailoverEndpoint, error) {
+	if len(hosts) == 0 {
+		return nil, ErrInvalidHost
+	}
+	f := &FailoverEndpoint{MaxFailures: DefaultMaxFailures, Cooldown: DefaultFailoverCooldown}
+	for _, host := range hosts {
+		if len(host) == 0 {
+			return nil, ErrInvalidHost
+		}
+		f.hosts = append(f.hosts, &failoverHost{host: host})
+	}
+	return f, nil
+}
+
+// DialAddress returns the next healthy host in rotation. When every host is
+// unhealthy the one closest to the end of its cooldown is returned.
+func (f *FailoverEndpoint) DialAddress() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for i := 0; i < len(f.hosts); i++ {
+		h := f.hosts[(f.next+i)%len(f.hosts)]
+		if !now.Before(h.unhealthyUntil) {
+			f.next = (f.next + i + 1) % len(f.hosts)
+			return h.host, nil
+		}
+	}
+	soonest := f.hosts[0]
+	for _, h := range f.hosts[1:] {
+		if h.unhealthyUntil.Before(soonest.unhealthyUntil) {
+			soonest = h
+		}
+	}
+	f.logger().WithFields(Fields{"func": "DialAddress", "host": soonest.host}).Warn(ErrNoHealthyEndpoint)
+	return soonest.host, nil
+}
+
+func (f *FailoverEndpoint) ReportSuccess(host string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if h := f.find(host); h != nil {
+		h.failures = 0
+		h.unhealthyUntil = time.Time{}
+	}
+}
+
+func (f *FailoverEndpoint) ReportFailure(host string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	h := f.find(host)
+	if h == nil {
+		return
+	}
+	h.failures++
+	maxFailures := f.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = DefaultMaxFailures
+	}
+	if h.failures >= maxFailures {
+		f.markUnhealthy(h)
+		f.logger().WithFields(Fields{"func": "ReportFailure", "host": host, "failures": h.failures}).Warn(err)
+	}
+}
+
+// Probe runs CheckHealth against every host with client and takes the hosts
+// that fail out of rotation right away
+func (f *FailoverEndpoint) Probe(ctx context.Context, client *http.Client) {
+	for _, status := range f.Hosts() {
+		probe := RPCClient{Endpoint: SolanaEndpoint{Host: status.Host}, Client: client}
+		healthy := probe.CheckHealth(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		f.mu.Lock()
+		if h := f.find(status.Host); h != nil {
+			if healthy {
+				h.failures = 0
+				h.unhealthyUntil = time.Time{}
+			} else {
+				h.failures++
+				f.markUnhealthy(h)
+			}
+		}
+		f.mu.Unlock()
+	}
+}
+
+// Hosts returns the state of every host, in configuration order
+func (f *FailoverEndpoint) Hosts() []HostStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	statuses := make([]HostStatus, len(f.hosts))
+	for i, h := range f.hosts {
+		statuses[i] = HostStatus{Host: h.host, Healthy: !now.Before(h.unhealthyUntil), ConsecutiveFailures: h.failures, UnhealthyUntil: h.unhealthyUntil}
+	}
+	return statuses
+}
+
+func (f *FailoverEndpoint) logger() Logger {
+	if f.Logger == nil {
+		return NopLogger{}
+	}
+	return f.Logger
+}
+
+func (f *FailoverEndpoint) markUnhealthy(h *failoverHost) {
+	cooldown := f.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultFailoverCooldown
+	}
+	h.unhealthyUntil = time.Now().Add(cooldown)
+}
+
+func (f *FailoverEndpoint) find(host string) *failoverHost {
+	for _, h := range f.hosts {
+		if h.host == host {
+			return h
+		}
+	}
+	return nil
+}
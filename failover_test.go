@@ -0,0 +1,67 @@
+package solanarpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailoverEndpoint(t *testing.T) {
+	var recovered int32
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/health" && atomic.LoadInt32(&recovered) == 1 {
+			w.Write([]byte("ok"))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/health" {
+			w.Write([]byte("ok"))
+			return
+		}
+		serveBalance(w, req)
+	}))
+	defer up.Close()
+
+	endpoint, err := NewFailoverEndpoint(down.URL, up.URL)
+	assert.NoError(t, err)
+	endpoint.MaxFailures = 2
+	endpoint.Cooldown = time.Hour
+	client := RPCClient{Endpoint: endpoint, Retry: fastRetryPolicy()}
+	client.Retry.MaxAttempts = 2
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		resp, err := client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+		assert.NoError(t, err)
+		assert.Equal(t, up.URL, resp.Host)
+	}
+	hosts := endpoint.Hosts()
+	assert.False(t, hosts[0].Healthy, "failing host should be out of rotation")
+	assert.True(t, hosts[1].Healthy)
+	addr, _ := endpoint.DialAddress()
+	assert.Equal(t, up.URL, addr)
+
+	// a success brings a host back, a failed probe takes it out again
+	endpoint.ReportSuccess(down.URL)
+	assert.True(t, endpoint.Hosts()[0].Healthy)
+	endpoint.Probe(ctx, http.DefaultClient)
+	hosts = endpoint.Hosts()
+	assert.False(t, hosts[0].Healthy, "failed probe should take the host out")
+	assert.True(t, hosts[1].Healthy)
+
+	// a passing probe brings a host back before its cooldown ends
+	atomic.StoreInt32(&recovered, 1)
+	endpoint.Probe(ctx, http.DefaultClient)
+	assert.True(t, endpoint.Hosts()[0].Healthy, "passing probe should bring the host back")
+
+	_, err = NewFailoverEndpoint()
+	assert.Equal(t, ErrInvalidHost, err)
+}
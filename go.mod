@@ -3,6 +3,7 @@ module solanarpc
 go 1.16
 
 require (
+	github.com/gorilla/websocket v1.4.2
 	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.7.0
 )
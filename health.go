@@ -0,0 +1,258 @@
+package solanarpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultHealthInterval = 10 * time.Second
+	// healthEventBuffer is the number of undelivered events a subscriber may
+	// hold before newer ones are dropped
+	healthEventBuffer = 16
+)
+
+type HealthStatus int
+
+const (
+	// HealthUnknown means the node could not be asked or gave no usable answer
+	HealthUnknown HealthStatus = iota
+	HealthOK
+	// HealthBehind means the node reported itself unhealthy
+	HealthBehind
+)
+
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthOK:
+		return "ok"
+	case HealthBehind:
+		return "behind"
+	default:
+		return "unknown"
+	}
+}
+
+// Health is the answer of a node to getHealth
+type Health struct {
+	Status HealthStatus
+	// NumSlotsBehind is how far a HealthBehind node is behind; 0 when it did not say
+	NumSlotsBehind uint64
+	// Err is why the status is HealthUnknown
+	Err error
+}
+
+// GetHealth asks the node for its health with getHealth. A node that answers,
+// healthy or behind, gives a nil error; the error is also stored in Health.Err.
+// Health checks are never retried, the answer has to reflect the node as it is.
+func (r *RPCClient) GetHealth(ctx context.Context) (Health, error) {
+	probe := *r
+	probe.Retry = nil
+	id := RandomID()
+	rpcReq := RPCRequest{Version: "2.0", ID: id, Method: "getHealth"}
+	resp, err := probe.DoPostRequest(ctx, rpcReq)
+	if err == nil && resp.ID != id {
+		err = ErrIDMismatch
+	}
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetHealth"}).Error(err)
+		return Health{Err: err}, err
+	}
+	health, err := parseHealth(resp)
+	if err != nil {
+		r.logger().WithFields(Fields{"func": "GetHealth"}).Error(err)
+	}
+	return health, err
+}
+
+func parseHealth(resp *RPCResponse) (Health, error) {
+	if resp.Error.Code != 0 {
+		err := resp.Error.Err()
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == ErrCodeNodeUnhealthy {
+			behind, _ := rpcErr.NumSlotsBehind()
+			return Health{Status: HealthBehind, NumSlotsBehind: behind}, nil
+		}
+		return Health{Err: err}, err
+	}
+	var result string
+	if err := json.Unmarshal(resp.Result, &result); err != nil || result != "ok" {
+		return Health{Err: ErrJSONParseError}, ErrJSONParseError
+	}
+	return Health{Status: HealthOK}, nil
+}
+
+// HealthEvent is published by a HealthMonitor when the health of a host changes
+type HealthEvent struct {
+	Host     string
+	Previous Health
+	Current  Health
+	Time     time.Time
+}
+
+// HealthMonitor probes a set of hosts with getHealth every Interval and
+// publishes the changes to its subscribers. When Reporter is set, every probe
+// result is reported to it as well, e.g. to a FailoverEndpoint of the same hosts.
+type HealthMonitor struct {
+	Interval time.Duration
+	Reporter EndpointReporter
+	// Logger receives state changes; nil discards them
+	Logger Logger
+
+	client *http.Client
+	hosts  []string
+
+	mu     sync.Mutex
+	states map[string]Health
+	subs   map[chan HealthEvent]struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewHealthMonitor monitors hosts with client; a nil client means http.DefaultClient
+func NewHealthMonitor(client *http.Client, hosts ...string) (*HealthMonitor, error) {
+	if len(hosts) == 0 {
+		return nil, ErrInvalidHost
+	}
+	for _, host := range hosts {
+		if len(host) == 0 {
+			return nil, ErrInvalidHost
+		}
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HealthMonitor{
+		Interval: DefaultHealthInterval,
+		client:   client,
+		hosts:    hosts,
+		states:   make(map[string]Health),
+		subs:     make(map[chan HealthEvent]struct{}),
+	}, nil
+}
+
+// Start probes every host right away and then every Interval, until ctx is
+// done or Stop is called. Starting a running monitor does nothing.
+func (m *HealthMonitor) Start(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		return
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	go m.run(ctx, m.done)
+}
+
+// Stop ends the probes and waits for the one in flight
+func (m *HealthMonitor) Stop() {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Subscribe returns a channel of health changes and a function ending the
+// subscription. Events are dropped while the channel is full.
+func (m *HealthMonitor) Subscribe() (<-chan HealthEvent, func()) {
+	ch := make(chan HealthEvent, healthEventBuffer)
+	m.mu.Lock()
+	m.subs[ch] = struct{}{}
+	m.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			delete(m.subs, ch)
+			m.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Health returns the last known health of host
+func (m *HealthMonitor) Health(host string) Health {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states[host]
+}
+
+// Probe checks every host once and publishes the changes
+func (m *HealthMonitor) Probe(ctx context.Context) {
+	for _, host := range m.hosts {
+		probeCtx, cancel := context.WithTimeout(ctx, m.interval())
+		probe := RPCClient{Endpoint: SolanaEndpoint{Host: host}, Client: m.client}
+		health, _ := probe.GetHealth(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if m.Reporter != nil {
+			switch {
+			case health.Status == HealthOK:
+				m.Reporter.ReportSuccess(host)
+			case health.Err != nil:
+				m.Reporter.ReportFailure(host, health.Err)
+			default:
+				m.Reporter.ReportFailure(host, ErrNodeUnhealthy)
+			}
+		}
+		m.update(host, health)
+	}
+}
+
+func (m *HealthMonitor) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(m.interval())
+	defer ticker.Stop()
+	for {
+		m.Probe(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *HealthMonitor) update(host string, health Health) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	previous, known := m.states[host]
+	m.states[host] = health
+	if known && previous.Status == health.Status {
+		return
+	}
+	event := HealthEvent{Host: host, Previous: previous, Current: health, Time: time.Now()}
+	m.logger().WithFields(Fields{"func": "HealthMonitor", "host": host, "previous": previous.Status, "current": health.Status}).Info("health changed")
+	for ch := range m.subs {
+		select {
+		case ch <- event:
+		default:
+			m.logger().WithFields(Fields{"func": "HealthMonitor", "host": host}).Warn("subscriber full, event dropped")
+		}
+	}
+}
+
+func (m *HealthMonitor) interval() time.Duration {
+	if m.Interval <= 0 {
+		return DefaultHealthInterval
+	}
+	return m.Interval
+}
+
+func (m *HealthMonitor) logger() Logger {
+	if m.Logger == nil {
+		return NopLogger{}
+	}
+	return m.Logger
+}
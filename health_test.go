@@ -0,0 +1,92 @@
+package solanarpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHealth(t *testing.T) {
+	var answer string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0",%s,"id":%d}`, answer, rpcReq.ID)
+	}))
+	defer srv.Close()
+
+	client := RPCClient{Retry: fastRetryPolicy()}
+	assert.NoError(t, client.Init(srv.URL))
+	ctx := context.Background()
+
+	answer = `"result":"ok"`
+	health, err := client.GetHealth(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Health{Status: HealthOK}, health)
+
+	answer = `"error":{"code":-32005,"message":"Node is behind by 42 slots","data":{"numSlotsBehind":42}}`
+	health, err = client.GetHealth(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Health{Status: HealthBehind, NumSlotsBehind: 42}, health)
+
+	answer = `"error":{"code":-32005,"message":"Node is unhealthy","data":{"numSlotsBehind":null}}`
+	health, err = client.GetHealth(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Health{Status: HealthBehind}, health)
+
+	answer = `"error":{"code":-32601,"message":"Method not found"}`
+	health, err = client.GetHealth(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, HealthUnknown, health.Status)
+	assert.Equal(t, err, health.Err)
+}
+
+func TestHealthMonitor(t *testing.T) {
+	var behind int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		if atomic.LoadInt32(&behind) == 1 {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Node is behind","data":{"numSlotsBehind":7}},"id":%d}`, rpcReq.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"ok","id":%d}`, rpcReq.ID)
+	}))
+	defer srv.Close()
+
+	failover, err := NewFailoverEndpoint(srv.URL)
+	assert.NoError(t, err)
+	failover.MaxFailures = 1
+	monitor, err := NewHealthMonitor(nil, srv.URL)
+	assert.NoError(t, err)
+	monitor.Interval = 10 * time.Millisecond
+	monitor.Reporter = failover
+	events, unsubscribe := monitor.Subscribe()
+	defer unsubscribe()
+
+	monitor.Start(context.Background())
+	defer monitor.Stop()
+	event := <-events
+	assert.Equal(t, srv.URL, event.Host)
+	assert.Equal(t, HealthUnknown, event.Previous.Status)
+	assert.Equal(t, HealthOK, event.Current.Status)
+
+	atomic.StoreInt32(&behind, 1)
+	event = <-events
+	assert.Equal(t, HealthOK, event.Previous.Status)
+	assert.Equal(t, Health{Status: HealthBehind, NumSlotsBehind: 7}, event.Current)
+	assert.Equal(t, event.Current, monitor.Health(srv.URL))
+	assert.False(t, failover.Hosts()[0].Healthy)
+
+	monitor.Stop()
+	_, err = NewHealthMonitor(nil)
+	assert.Equal(t, ErrInvalidHost, err)
+}
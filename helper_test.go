@@ -0,0 +1,38 @@
+package solanarpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// balanceResult is what the stand-in nodes of these tests answer by default
+const balanceResult = `{"context":{"slot":1},"value":7}`
+
+func fastRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Multiplier: 2}
+}
+
+func decodeRequest(req *http.Request, rpcReq *RPCRequest) {
+	json.NewDecoder(req.Body).Decode(rpcReq)
+}
+
+// writeResult answers the request with ID id with the raw JSON result
+func writeResult(w http.ResponseWriter, id uint64, result string) {
+	w.Header().Set("Content-Type", HTTPContentType)
+	fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%s,"id":%d}`, result, id)
+}
+
+// serveBalance answers any request with balanceResult
+func serveBalance(w http.ResponseWriter, req *http.Request) {
+	rpcReq := RPCRequest{}
+	decodeRequest(req, &rpcReq)
+	writeResult(w, rpcReq.ID, balanceResult)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
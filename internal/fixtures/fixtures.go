@@ -1,6 +1,8 @@
-package solanarpc
+// Package fixtures holds responses recorded from real Solana nodes, shared by
+// the tests of solanarpc and by solanarpctest.
+package fixtures
 
-var testResultAccountExsitBase58 string = `{
+var AccountExsitBase58 = `{
 	"jsonrpc": "2.0",
 	"result": {
 	  "context": {
@@ -19,7 +21,7 @@ var testResultAccountExsitBase58 string = `{
 	},
 	"id": 1
   }`
-var testResultAccountNotExist string = `{
+var AccountNotExist = `{
     "jsonrpc": "2.0",
     "result": {
         "context": {
@@ -30,7 +32,7 @@ var testResultAccountNotExist string = `{
     "id": 2
 }`
 
-var testResultAccountError string = `{
+var AccountError = `{
     "jsonrpc": "2.0",
     "error": {
         "code": -32602,
@@ -39,7 +41,7 @@ var testResultAccountError string = `{
     "id": 2
 }`
 
-var testResultBalance01 string = `{
+var Balance01 = `{
     "jsonrpc": "2.0",
     "result": {
         "context": {
@@ -50,7 +52,7 @@ var testResultBalance01 string = `{
     "id": 1
 }`
 
-var testResultBlockCommitment01 string = `{
+var BlockCommitment01 = `{
 	"jsonrpc":"2.0",
 	"result":{
 	  "commitment":[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,10,32],
@@ -59,25 +61,25 @@ var testResultBlockCommitment01 string = `{
 	"id":1
   }`
 
-var testResultBlockTime01 string = `{
+var BlockTime01 = `{
     "jsonrpc": "2.0",
     "result": 1574721591,
     "id": 1
 }`
 
-var testResultBlockTime02 string = `{
+var BlockTime02 = `{
     "jsonrpc": "2.0",
     "result": null,
     "id": 1
 }`
 
-var testResultBlockTime03 string = `{
+var BlockTime03 = `{
     "jsonrpc": "2.0",
     "result": -123,
     "id": 1
 }`
 
-var testResultClusterNodes01 string = `{
+var ClusterNodes01 = `{
 	"jsonrpc": "2.0",
 	"result": [
 	  {
@@ -99,7 +101,7 @@ var testResultClusterNodes01 string = `{
 	"id": 1
   }`
 
-var testResultConfirmedBlock01 string = `{
+var ConfirmedBlock01 = `{
 	"jsonrpc": "2.0",
 	"result": {
 	  "blockTime": null,
@@ -171,13 +173,13 @@ var testResultConfirmedBlock01 string = `{
 	"id": 1
   }`
 
-var testResultConfirmedBlock02 string = `{
+var ConfirmedBlock02 = `{
 	"jsonrpc": "2.0",
 	"result": null,
 	"id": 1
   }`
 
-var testResultConfirmedBlock03 string = `{
+var ConfirmedBlock03 = `{
     "jsonrpc": "2.0",
     "error": {
         "code": -32007,
@@ -186,7 +188,7 @@ var testResultConfirmedBlock03 string = `{
     "id": 1
 }`
 
-var testResultConfirmedBlock04 string = `{
+var ConfirmedBlock04 = `{
     "jsonrpc": "2.0",
     "result": {
         "blockTime": 1620374821,
@@ -204,7 +206,7 @@ var testResultConfirmedBlock04 string = `{
     },
     "id": 1
 }`
-var testResultConfirmedBlock05 string = `{
+var ConfirmedBlock05 = `{
     "jsonrpc": "2.0",
     "result": {
         "blockTime": 1620374821,
@@ -232,7 +234,7 @@ var testResultConfirmedBlock05 string = `{
     "id": 1
 }`
 
-var testResultConfirmedBlocks01 string = `{
+var ConfirmedBlocks01 = `{
     "jsonrpc": "2.0",
     "result": [
         77228566,
@@ -244,13 +246,13 @@ var testResultConfirmedBlocks01 string = `{
     "id": 1
 }`
 
-var testResultConfirmedBlocks02 string = `{
+var ConfirmedBlocks02 = `{
     "jsonrpc": "2.0",
     "result": [],
     "id": 1
 }`
 
-var testResultConfirmedBlocks03 string = `{
+var ConfirmedBlocks03 = `{
     "jsonrpc": "2.0",
     "error": {
         "code": -32602,
@@ -259,7 +261,7 @@ var testResultConfirmedBlocks03 string = `{
     "id": 1
 }`
 
-var testResultConfirmedBlockWithLimit01 string = `{
+var ConfirmedBlockWithLimit01 = `{
     "jsonrpc": "2.0",
     "result": [
         77118633,
@@ -269,7 +271,7 @@ var testResultConfirmedBlockWithLimit01 string = `{
     "id": 1
 }`
 
-var testResultConfirmedSignaturesForAddress201 string = `{
+var ConfirmedSignaturesForAddress201 = `{
     "jsonrpc": "2.0",
     "result": [
         {
@@ -284,7 +286,7 @@ var testResultConfirmedSignaturesForAddress201 string = `{
     "id": 1
 }`
 
-var testResultTokenSupply01 string = `{
+var TokenSupply01 = `{
   "jsonrpc": "2.0",
   "result": {
       "context": {
@@ -300,7 +302,7 @@ var testResultTokenSupply01 string = `{
   "id": 1
 }`
 
-var testResultTokenAccountBalance01 string = `{
+var TokenAccountBalance01 = `{
   "jsonrpc": "2.0",
   "result": {
       "context": {
@@ -316,7 +318,7 @@ var testResultTokenAccountBalance01 string = `{
   "id": 1
 }`
 
-var testResultTokenAccountBalance02 string = `{
+var TokenAccountBalance02 = `{
   "jsonrpc": "2.0",
   "error": {
       "code": -32602,
@@ -327,7 +329,7 @@ var testResultTokenAccountBalance02 string = `{
 
 // TODO: Report to Solana Team that a , after uiAmountString make golang parse does not work
 // TODO: Ask for the Data struct of data return object
-var testResultTokenAccountsByDelegate01 string = `{
+var TokenAccountsByDelegate01 = `{
   "jsonrpc": "2.0",
   "result": {
     "context": {
@@ -365,7 +367,7 @@ var testResultTokenAccountsByDelegate01 string = `{
   "id": 1
 }`
 
-var testResultTokenAccountsByDelegate02 string = `{
+var TokenAccountsByDelegate02 = `{
   "jsonrpc": "2.0",
   "result": {
       "context": {
@@ -386,3 +388,195 @@ var testResultTokenAccountsByDelegate02 string = `{
   },
   "id": 1
 }`
+
+var Transaction01 = `{
+  "jsonrpc": "2.0",
+  "result": {
+    "blockTime": 1624821990,
+    "meta": {
+      "err": null,
+      "fee": 5000,
+      "innerInstructions": [],
+      "logMessages": [
+        "Program 11111111111111111111111111111111 invoke [1]",
+        "Program 11111111111111111111111111111111 success"
+      ],
+      "postBalances": [499998932500, 26858640, 1],
+      "postTokenBalances": [],
+      "preBalances": [499998937500, 26853640, 1],
+      "preTokenBalances": [],
+      "rewards": [],
+      "status": {
+        "Ok": null
+      }
+    },
+    "slot": 430,
+    "transaction": {
+      "message": {
+        "accountKeys": [
+          "3UVYmECPPMZSCqWKfENfuoTv51fTDTWicX9xmBD2euKe",
+          "AjozzgE83A3x1sHNUR64hfH7zaEBWeMaFuAN9kQgujrc",
+          "11111111111111111111111111111111"
+        ],
+        "header": {
+          "numReadonlySignedAccounts": 0,
+          "numReadonlyUnsignedAccounts": 1,
+          "numRequiredSignatures": 1
+        },
+        "instructions": [
+          {
+            "accounts": [0, 1],
+            "data": "3Bxs4Bc3VYuGVB19",
+            "programIdIndex": 2
+          }
+        ],
+        "recentBlockhash": "mfcyqEXB3DnHXki6KjjmZck6YjmZLvpAByy2fj4nh6B"
+      },
+      "signatures": [
+        "2nBhEBYYvfaAe16UMNqRHre4YNSskvuYgx3M6E4JP1oDYvZEJHvoPzyUidNgNX5r9sTyN1J9UxtbCXy2rqYcuyuv"
+      ]
+    }
+  },
+  "id": 1
+}`
+
+var Transaction02 = `{
+  "jsonrpc": "2.0",
+  "result": {
+    "blockTime": null,
+    "meta": {
+      "err": {
+        "InstructionError": [1, {"Custom": 1}]
+      },
+      "fee": 10000,
+      "innerInstructions": [
+        {
+          "index": 1,
+          "instructions": [
+            {
+              "accounts": [2, 3, 0],
+              "data": "3DdGGhkhJbjm",
+              "programIdIndex": 4
+            }
+          ]
+        }
+      ],
+      "logMessages": [
+        "Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA invoke [1]",
+        "Program log: Error: insufficient funds",
+        "Program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA failed: custom program error: 0x1"
+      ],
+      "postBalances": [2039280, 2039280, 1461600],
+      "postTokenBalances": [
+        {
+          "accountIndex": 2,
+          "mint": "3wyAj7Rt1TWVPZVteFJPLa26JmLvdb1CAKEFZm3NY75E",
+          "owner": "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12",
+          "uiTokenAmount": {
+            "amount": "5065734",
+            "decimals": 2,
+            "uiAmount": 50657.34,
+            "uiAmountString": "50657.34"
+          }
+        }
+      ],
+      "preBalances": [2049280, 2039280, 1461600],
+      "preTokenBalances": [
+        {
+          "accountIndex": 2,
+          "mint": "3wyAj7Rt1TWVPZVteFJPLa26JmLvdb1CAKEFZm3NY75E",
+          "owner": "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12",
+          "uiTokenAmount": {
+            "amount": "5065734",
+            "decimals": 2,
+            "uiAmount": 50657.34,
+            "uiAmountString": "50657.34"
+          }
+        }
+      ],
+      "rewards": null,
+      "status": {
+        "Err": {
+          "InstructionError": [1, {"Custom": 1}]
+        }
+      }
+    },
+    "slot": 76954704,
+    "transaction": [
+      "AVj7dxHlQ9IrvdYVIjuiRFs1jLaDMHixgrv+qtHBwz51L4/ZLdVdeJvVbYnHTNDp1nAmr7s5BNw2BknVMJDANAAB",
+      "base64"
+    ]
+  },
+  "id": 1
+}`
+
+var Transaction03 = `{
+  "jsonrpc": "2.0",
+  "result": {
+    "blockTime": 1624821990,
+    "meta": {
+      "err": null,
+      "fee": 5000,
+      "innerInstructions": [
+        {
+          "index": 0,
+          "instructions": [
+            {
+              "parsed": {
+                "info": {
+                  "destination": "AjozzgE83A3x1sHNUR64hfH7zaEBWeMaFuAN9kQgujrc",
+                  "lamports": 5000,
+                  "source": "3UVYmECPPMZSCqWKfENfuoTv51fTDTWicX9xmBD2euKe"
+                },
+                "type": "transfer"
+              },
+              "program": "system",
+              "programId": "11111111111111111111111111111111"
+            }
+          ]
+        }
+      ],
+      "logMessages": [
+        "Program Vote111111111111111111111111111111111111111 invoke [1]",
+        "Program 11111111111111111111111111111111 invoke [2]",
+        "Program 11111111111111111111111111111111 success",
+        "Program Vote111111111111111111111111111111111111111 success"
+      ],
+      "postBalances": [499998932500, 26858640, 1, 1],
+      "postTokenBalances": [],
+      "preBalances": [499998937500, 26853640, 1, 1],
+      "preTokenBalances": [],
+      "rewards": [],
+      "status": {
+        "Ok": null
+      }
+    },
+    "slot": 430,
+    "transaction": {
+      "message": {
+        "accountKeys": [
+          {"pubkey": "3UVYmECPPMZSCqWKfENfuoTv51fTDTWicX9xmBD2euKe", "signer": true, "writable": true},
+          {"pubkey": "AjozzgE83A3x1sHNUR64hfH7zaEBWeMaFuAN9kQgujrc", "signer": false, "writable": true},
+          {"pubkey": "11111111111111111111111111111111", "signer": false, "writable": false},
+          {"pubkey": "Vote111111111111111111111111111111111111111", "signer": false, "writable": false}
+        ],
+        "instructions": [
+          {
+            "accounts": [
+              "3UVYmECPPMZSCqWKfENfuoTv51fTDTWicX9xmBD2euKe",
+              "AjozzgE83A3x1sHNUR64hfH7zaEBWeMaFuAN9kQgujrc",
+              "11111111111111111111111111111111"
+            ],
+            "data": "3Bxs4Bc3VYuGVB19",
+            "programId": "Vote111111111111111111111111111111111111111"
+          }
+        ],
+        "recentBlockhash": "mfcyqEXB3DnHXki6KjjmZck6YjmZLvpAByy2fj4nh6B"
+      },
+      "signatures": [
+        "2nBhEBYYvfaAe16UMNqRHre4YNSskvuYgx3M6E4JP1oDYvZEJHvoPzyUidNgNX5r9sTyN1J9UxtbCXy2rqYcuyuv"
+      ]
+    }
+  },
+  "id": 1
+}`
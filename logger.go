@@ -0,0 +1,90 @@
+package solanarpc
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedParam replaces params that must not reach the logs
+const RedactedParam = "[REDACTED]"
+
+// RedactedMethods lists methods whose params are replaced by RedactedParam
+// when requests are logged, since they carry signed transactions
+var RedactedMethods = map[string]bool{
+	"sendTransaction":     true,
+	"simulateTransaction": true,
+}
+
+type Fields map[string]interface{}
+
+// Logger is the logging interface RPCClient, PubSubClient and the parsers write to
+type Logger interface {
+	WithFields(fields Fields) Logger
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+}
+
+// NopLogger discards everything; it is the default of every client
+type NopLogger struct{}
+
+func (l NopLogger) WithFields(fields Fields) Logger { return l }
+func (NopLogger) Debug(args ...interface{})         {}
+func (NopLogger) Info(args ...interface{})          {}
+func (NopLogger) Warn(args ...interface{})          {}
+func (NopLogger) Error(args ...interface{})         {}
+
+type logrusLogger struct {
+	entry logrus.FieldLogger
+}
+
+// NewLogrusLogger adapts a logrus logger or entry, e.g. logrus.StandardLogger()
+func NewLogrusLogger(l logrus.FieldLogger) Logger {
+	return logrusLogger{entry: l}
+}
+
+func (l logrusLogger) WithFields(fields Fields) Logger {
+	return logrusLogger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
+func (l logrusLogger) Debug(args ...interface{}) { l.entry.Debug(args...) }
+func (l logrusLogger) Info(args ...interface{})  { l.entry.Info(args...) }
+func (l logrusLogger) Warn(args ...interface{})  { l.entry.Warn(args...) }
+func (l logrusLogger) Error(args ...interface{}) { l.entry.Error(args...) }
+
+// parseLogger holds the Logger of the package level Parse* functions, which
+// may run on any goroutine while SetParseLogger is called
+var parseLogger atomic.Value
+
+// loggerBox gives parseLogger one concrete type whatever the Logger
+type loggerBox struct {
+	Logger
+}
+
+// SetParseLogger sets the logger of the Parse* functions; nil restores NopLogger
+func SetParseLogger(logger Logger) {
+	if logger == nil {
+		logger = NopLogger{}
+	}
+	parseLogger.Store(loggerBox{logger})
+}
+
+func parseLog() Logger {
+	if box, ok := parseLogger.Load().(loggerBox); ok {
+		return box.Logger
+	}
+	return NopLogger{}
+}
+
+// redactParams returns the params of rpcReq safe for logging
+func redactParams(rpcReq *RPCRequest) []interface{} {
+	if !RedactedMethods[rpcReq.Method] {
+		return rpcReq.Params
+	}
+	redacted := make([]interface{}, len(rpcReq.Params))
+	for i := range redacted {
+		redacted[i] = RedactedParam
+	}
+	return redacted
+}
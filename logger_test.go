@@ -0,0 +1,81 @@
+package solanarpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordLogger keeps the fields of every Debug and Error call
+type recordLogger struct {
+	fields  Fields
+	records *[]Fields
+}
+
+func (l recordLogger) WithFields(fields Fields) Logger {
+	merged := Fields{}
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return recordLogger{fields: merged, records: l.records}
+}
+func (l recordLogger) Debug(args ...interface{}) { *l.records = append(*l.records, l.fields) }
+func (l recordLogger) Info(args ...interface{})  {}
+func (l recordLogger) Warn(args ...interface{})  {}
+func (l recordLogger) Error(args ...interface{}) { *l.records = append(*l.records, l.fields) }
+
+func TestClientLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"sig","id":%d}`, rpcReq.ID)
+	}))
+	defer srv.Close()
+
+	records := []Fields{}
+	client := RPCClient{Logger: recordLogger{records: &records}}
+	assert.NoError(t, client.Init(srv.URL))
+	rpcReq := NewRPCRequest("sendTransaction", "4hXTCkRzt9WyecNzV1XPgCDfGAZzQKNxLXgynz5QDuWWPSAZBZSHptvWRL3BjCvzUXRdKvHL2b7yGrRQcWyaqsaBCncVG7BFggS8w9snUts67BSh3EqKpXLUm5UMHfD7ZBe9GhARjbNQMLJ1QD3Spr6oMTBU6EhdB4RD8CP2xUxr2u3d6fos36PD98XS6oX8TQjLpsMwncs5DAMiD4nNnR8NBfyghGCWvCVifVwvA8B8TJxE1aiyiv2L429BCWfyzAme5sZW8rDb14NeCQHhZbtNqfXhcp2tAnaAT")
+	_, err := client.DoPostRequest(context.Background(), rpcReq)
+	assert.NoError(t, err)
+
+	assert.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, "sendTransaction", record["method"])
+	assert.Equal(t, rpcReq.ID, record["id"])
+	assert.Equal(t, srv.URL, record["endpoint"])
+	assert.Contains(t, record, "latency")
+	assert.Equal(t, []interface{}{RedactedParam}, record["params"])
+}
+
+func TestSetParseLogger(t *testing.T) {
+	defer SetParseLogger(nil)
+	bad := &RPCResponse{Result: []byte(`{"context":{"slot":1},"value":"seven"}`)}
+
+	// parsers may run while the logger is replaced
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			ParseBalanceResponse(bad)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		SetParseLogger(NopLogger{})
+	}
+	<-done
+
+	records := []Fields{}
+	SetParseLogger(recordLogger{records: &records})
+	_, _, err := ParseBalanceResponse(bad)
+	assert.Error(t, err)
+	assert.Equal(t, []Fields{{"func": "ParseBalanceResponse"}}, records)
+}
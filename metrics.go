@@ -0,0 +1,240 @@
+package solanarpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency
+// histogram kept by MemoryMetrics
+var DefaultLatencyBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// RequestMetric describes one HTTP round trip. Batches are observed once,
+// under the method "batch".
+type RequestMetric struct {
+	Method   string
+	Endpoint string
+	Latency  time.Duration
+	// RequestSize and ResponseSize are body sizes in bytes
+	RequestSize  int
+	ResponseSize int
+	// Err is the transport error or the RPC error of the response, if any
+	Err error
+}
+
+// Metrics receives an observation for every request sent by an RPCClient.
+// ObserveRequest is called from the request goroutine and must not block.
+type Metrics interface {
+	ObserveRequest(m RequestMetric)
+}
+
+// ErrorCode is the label errors are counted under: the code of an RPCError,
+// "http_<status>" for an HTTPError and "transport" for anything else
+func ErrorCode(err error) string {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return strconv.Itoa(rpcErr.Code)
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return "http_" + strconv.Itoa(httpErr.StatusCode)
+	}
+	return "transport"
+}
+
+// endpointLabel keeps scheme and host of an endpoint URL, leaving out paths
+// and queries that often carry provider API keys
+func endpointLabel(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || len(u.Host) == 0 {
+		return endpoint
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func (r *RPCClient) observe(method, host string, start time.Time, requestSize, responseSize int, err error) {
+	if r.Metrics == nil {
+		return
+	}
+	r.Metrics.ObserveRequest(RequestMetric{
+		Method:       method,
+		Endpoint:     host,
+		Latency:      time.Since(start),
+		RequestSize:  requestSize,
+		ResponseSize: responseSize,
+		Err:          err,
+	})
+}
+
+// MethodStats are the totals of one method on one endpoint
+type MethodStats struct {
+	Method   string
+	Endpoint string
+	Requests uint64
+	// Errors counts failed requests by ErrorCode
+	Errors        map[string]uint64
+	RequestBytes  uint64
+	ResponseBytes uint64
+	// LatencyBuckets counts requests per bucket of MemoryMetrics.Buckets, the
+	// last one being +Inf. Counts are not cumulative.
+	LatencyBuckets []uint64
+	LatencySum     time.Duration
+}
+
+type methodKey struct {
+	method   string
+	endpoint string
+}
+
+// MemoryMetrics keeps the totals of every method and endpoint in memory.
+// It is an http.Handler serving them in the Prometheus text format.
+type MemoryMetrics struct {
+	// Buckets are the latency histogram bounds in seconds, in increasing
+	// order. They must not change once requests are observed.
+	Buckets []float64
+
+	mu    sync.Mutex
+	stats map[methodKey]*MethodStats
+}
+
+func NewMemoryMetrics() *MemoryMetrics {
+	return &MemoryMetrics{Buckets: DefaultLatencyBuckets, stats: make(map[methodKey]*MethodStats)}
+}
+
+func (m *MemoryMetrics) ObserveRequest(rm RequestMetric) {
+	key := methodKey{method: rm.Method, endpoint: endpointLabel(rm.Endpoint)}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stats == nil {
+		m.stats = make(map[methodKey]*MethodStats)
+	}
+	s, ok := m.stats[key]
+	if !ok {
+		s = &MethodStats{Method: key.method, Endpoint: key.endpoint, Errors: map[string]uint64{}, LatencyBuckets: make([]uint64, len(m.Buckets)+1)}
+		m.stats[key] = s
+	}
+	s.Requests++
+	if rm.Err != nil {
+		s.Errors[ErrorCode(rm.Err)]++
+	}
+	s.RequestBytes += uint64(rm.RequestSize)
+	s.ResponseBytes += uint64(rm.ResponseSize)
+	s.LatencySum += rm.Latency
+	bucket := sort.SearchFloat64s(m.Buckets, rm.Latency.Seconds())
+	if bucket >= len(s.LatencyBuckets) {
+		bucket = len(s.LatencyBuckets) - 1
+	}
+	s.LatencyBuckets[bucket]++
+}
+
+// Snapshot returns a copy of the totals, sorted by method and endpoint
+func (m *MemoryMetrics) Snapshot() []MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make([]MethodStats, 0, len(m.stats))
+	for _, s := range m.stats {
+		c := *s
+		c.Errors = make(map[string]uint64, len(s.Errors))
+		for code, n := range s.Errors {
+			c.Errors[code] = n
+		}
+		c.LatencyBuckets = append([]uint64(nil), s.LatencyBuckets...)
+		snapshot = append(snapshot, c)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Method != snapshot[j].Method {
+			return snapshot[i].Method < snapshot[j].Method
+		}
+		return snapshot[i].Endpoint < snapshot[j].Endpoint
+	})
+	return snapshot
+}
+
+// ServeHTTP writes the totals in the Prometheus text exposition format
+func (m *MemoryMetrics) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WritePrometheus(w)
+}
+
+func (m *MemoryMetrics) WritePrometheus(w io.Writer) error {
+	snapshot := m.Snapshot()
+	p := &promWriter{w: w}
+
+	p.header("solanarpc_requests_total", "counter", "RPC requests sent, by method and endpoint.")
+	for _, s := range snapshot {
+		p.sample("solanarpc_requests_total", labels(s), float64(s.Requests))
+	}
+	p.header("solanarpc_errors_total", "counter", "Failed RPC requests, by method, endpoint and error code.")
+	for _, s := range snapshot {
+		codes := make([]string, 0, len(s.Errors))
+		for code := range s.Errors {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			p.sample("solanarpc_errors_total", labels(s, "code", code), float64(s.Errors[code]))
+		}
+	}
+	p.header("solanarpc_request_duration_seconds", "histogram", "RPC request latency.")
+	for _, s := range snapshot {
+		var cumulative uint64
+		for i, n := range s.LatencyBuckets {
+			cumulative += n
+			le := "+Inf"
+			if i < len(m.Buckets) {
+				le = strconv.FormatFloat(m.Buckets[i], 'g', -1, 64)
+			}
+			p.sample("solanarpc_request_duration_seconds_bucket", labels(s, "le", le), float64(cumulative))
+		}
+		p.sample("solanarpc_request_duration_seconds_sum", labels(s), s.LatencySum.Seconds())
+		p.sample("solanarpc_request_duration_seconds_count", labels(s), float64(s.Requests))
+	}
+	p.header("solanarpc_request_bytes_total", "counter", "Bytes of RPC request bodies.")
+	for _, s := range snapshot {
+		p.sample("solanarpc_request_bytes_total", labels(s), float64(s.RequestBytes))
+	}
+	p.header("solanarpc_response_bytes_total", "counter", "Bytes of RPC response bodies.")
+	for _, s := range snapshot {
+		p.sample("solanarpc_response_bytes_total", labels(s), float64(s.ResponseBytes))
+	}
+	return p.err
+}
+
+func labels(s MethodStats, extra ...string) string {
+	pairs := append([]string{"method", s.Method, "endpoint", s.Endpoint}, extra...)
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+labelEscaper.Replace(pairs[i+1])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promWriter keeps the first write error so the exporter reads straight through
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) header(name, kind, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *promWriter) sample(name, labels string, value float64) {
+	p.printf("%s%s %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (p *promWriter) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
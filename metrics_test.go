@@ -0,0 +1,69 @@
+package solanarpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		if rpcReq.Method == "getBlockTime" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","error":{"code":-32004,"message":"Block not available"},"id":%d}`, rpcReq.ID)
+			return
+		}
+		writeResult(w, rpcReq.ID, balanceResult)
+	}))
+	defer srv.Close()
+
+	metrics := NewMemoryMetrics()
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL + "/?api-key=secret"}, WithMetrics(metrics))
+	assert.NoError(t, err)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+		assert.NoError(t, err)
+	}
+	client.GetBlockTime(ctx, 5)
+
+	stats := metrics.Snapshot()
+	assert.Len(t, stats, 2)
+	balance := stats[0]
+	assert.Equal(t, "getBalance", balance.Method)
+	assert.Equal(t, srv.URL, balance.Endpoint, "endpoint label should drop the API key")
+	assert.Equal(t, uint64(2), balance.Requests)
+	assert.Empty(t, balance.Errors)
+	assert.True(t, balance.RequestBytes > 0 && balance.ResponseBytes > 0)
+	var observed uint64
+	for _, n := range balance.LatencyBuckets {
+		observed += n
+	}
+	assert.Equal(t, uint64(2), observed)
+	assert.Equal(t, map[string]uint64{"-32004": 1}, stats[1].Errors)
+
+	metrics.ObserveRequest(RequestMetric{Method: "getSlot", Endpoint: "x", Latency: 30 * time.Millisecond, Err: &HTTPError{StatusCode: 429}})
+	out := bytes.Buffer{}
+	assert.NoError(t, metrics.WritePrometheus(&out))
+	text := out.String()
+	assert.Contains(t, text, "# TYPE solanarpc_request_duration_seconds histogram\n")
+	assert.Contains(t, text, fmt.Sprintf(`solanarpc_requests_total{method="getBalance",endpoint="%s"} 2`, srv.URL))
+	assert.Contains(t, text, `solanarpc_errors_total{method="getSlot",endpoint="x",code="http_429"} 1`)
+	assert.Contains(t, text, `solanarpc_request_duration_seconds_bucket{method="getSlot",endpoint="x",le="0.025"} 0`)
+	assert.Contains(t, text, `solanarpc_request_duration_seconds_bucket{method="getSlot",endpoint="x",le="0.05"} 1`)
+	assert.Contains(t, text, `solanarpc_request_duration_seconds_bucket{method="getSlot",endpoint="x",le="+Inf"} 1`)
+	assert.False(t, strings.Contains(text, "secret"))
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, text, rec.Body.String())
+}
@@ -0,0 +1,91 @@
+package solanarpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Handler sends one RPC request and returns its response
+type Handler func(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error)
+
+// Middleware wraps a Handler. It may change rpcReq before calling next, and
+// inspect or replace the response and error it returns.
+type Middleware func(next Handler) Handler
+
+// Chain composes middlewares so the first one is the outermost
+func Chain(mws ...Middleware) Middleware {
+	return func(next Handler) Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// Use appends middlewares to the client. They run in the order they were added
+// around every DoPostRequest, outside rate limiting and retries.
+func (r *RPCClient) Use(mws ...Middleware) {
+	r.Middlewares = append(r.Middlewares, mws...)
+}
+
+type headerKey struct{}
+
+// WithHeader returns a ctx whose requests carry header in addition to the defaults
+func WithHeader(ctx context.Context, header http.Header) context.Context {
+	merged := http.Header{}
+	if parent, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		for k, v := range parent {
+			merged[k] = append([]string(nil), v...)
+		}
+	}
+	for k, v := range header {
+		merged[k] = append([]string(nil), v...)
+	}
+	return context.WithValue(ctx, headerKey{}, merged)
+}
+
+func headerFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerKey{}).(http.Header)
+	return header
+}
+
+// HeaderMiddleware adds header to every HTTP request
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error) {
+			return next(WithHeader(ctx, header), rpcReq)
+		}
+	}
+}
+
+// DumpMiddleware writes each request and its response or error to w, one
+// JSON document per line prefixed with --> or <--
+func DumpMiddleware(w io.Writer) Middleware {
+	var mu sync.Mutex
+	return func(next Handler) Handler {
+		return func(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error) {
+			dumped := *rpcReq
+			dumped.Params = redactParams(rpcReq)
+			reqDump, _ := json.Marshal(dumped)
+			mu.Lock()
+			fmt.Fprintf(w, "--> %s\n", reqDump)
+			mu.Unlock()
+
+			resp, err := next(ctx, rpcReq)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(w, "<-- error: %v\n", err)
+				return resp, err
+			}
+			respDump, _ := json.Marshal(resp)
+			fmt.Fprintf(w, "<-- %s\n", respDump)
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package solanarpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	var gotHeader, gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotHeader = req.Header.Get("X-Api-Key")
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		gotMethod = rpcReq.Method
+		writeResult(w, rpcReq.ID, balanceResult)
+	}))
+	defer srv.Close()
+
+	order := []string{}
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error) {
+				order = append(order, name+" before")
+				resp, err := next(ctx, rpcReq)
+				order = append(order, name+" after")
+				return resp, err
+			}
+		}
+	}
+	rewrite := func(next Handler) Handler {
+		return func(ctx context.Context, rpcReq *RPCRequest) (*RPCResponse, error) {
+			rpcReq.Method = "getBalance"
+			return next(ctx, rpcReq)
+		}
+	}
+	dump := new(bytes.Buffer)
+
+	client := RPCClient{}
+	assert.NoError(t, client.Init(srv.URL))
+	client.Use(trace("outer"), trace("inner"), HeaderMiddleware(http.Header{"X-Api-Key": {"secret"}}), rewrite, DumpMiddleware(dump))
+	_, err := client.DoPostRequest(context.Background(), NewRPCRequest("getBalanceTypo", "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, order)
+	assert.Equal(t, "secret", gotHeader)
+	assert.Equal(t, "getBalance", gotMethod)
+	lines := strings.Split(strings.TrimSpace(dump.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], `--> {"jsonrpc":"2.0"`))
+	assert.True(t, strings.HasPrefix(lines[1], `<-- {"jsonrpc":"2.0"`))
+
+}
+
+func TestDumpMiddlewareRedacts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"sig","id":%d}`, rpcReq.ID)
+	}))
+	defer srv.Close()
+
+	dump := new(bytes.Buffer)
+	client := RPCClient{}
+	assert.NoError(t, client.Init(srv.URL))
+	client.Use(DumpMiddleware(dump))
+	_, err := client.DoPostRequest(context.Background(), NewRPCRequest("sendTransaction", "c2lnbmVkLXBheWxvYWQ="))
+	assert.NoError(t, err)
+	assert.Contains(t, dump.String(), RedactedParam)
+	assert.NotContains(t, dump.String(), "c2lnbmVkLXBheWxvYWQ=")
+}
@@ -0,0 +1,658 @@
+package solanarpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// NotificationBufferSize is the number of undelivered notifications a
+// subscription holds before the read loop waits for the consumer
+const NotificationBufferSize = 64
+
+// closeTimeout bounds the write of the close frame, so Close returns on a dead peer
+const closeTimeout = time.Second
+
+type LogsFilter string
+
+const (
+	LogsFilterAll          LogsFilter = "all"
+	LogsFilterAllWithVotes LogsFilter = "allWithVotes"
+)
+
+// LogsMentions builds the logsSubscribe filter for transactions mentioning pubkey
+func LogsMentions(base58Pubkey string) interface{} {
+	return map[string][]string{"mentions": {base58Pubkey}}
+}
+
+// PubSubClient is a WebSocket client for the Solana PubSub API. One connection
+// carries every subscription; notifications are routed by subscription ID.
+type PubSubClient struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	nextID  uint64
+
+	mu      sync.Mutex
+	pending map[uint64]*pendingCall
+	subs    map[uint64]*Subscription
+	err     error
+	log     Logger
+	trace   Tracer
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type pendingCall struct {
+	reply chan pubSubMessage
+	// sub is registered by the read loop as soon as the reply arrives, so no
+	// notification sent right after the reply is lost
+	sub *Subscription
+	// abandoned is set when the caller gave up waiting; the subscription of
+	// a late reply is then cancelled instead of registered
+	abandoned bool
+}
+
+type pubSubMessage struct {
+	Version string           `json:"jsonrpc"`
+	ID      *uint64          `json:"id,omitempty"`
+	Error   RPCResponseError `json:"error,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  struct {
+		Subscription uint64          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params,omitempty"`
+}
+
+// DialPubSub opens a PubSub connection to a ws:// or wss:// url
+func DialPubSub(ctx context.Context, url string) (*PubSubClient, error) {
+	if len(url) == 0 {
+		return nil, ErrInvalidHost
+	}
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	c := &PubSubClient{
+		conn:    conn,
+		pending: make(map[uint64]*pendingCall),
+		subs:    make(map[uint64]*Subscription),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// Close drops the connection, which ends every subscription on the node, and
+// closes all notification channels
+func (c *PubSubClient) Close() error {
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(closeTimeout))
+	c.shutdown(ErrPubSubClosed)
+	return nil
+}
+
+// SetLogger sets where the client logs go; nil discards them
+func (c *PubSubClient) SetLogger(logger Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.log = logger
+}
+
+func (c *PubSubClient) logger() Logger {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.log == nil {
+		return NopLogger{}
+	}
+	return c.log
+}
+
+// SetTracer makes the client open a span per call and per notification; nil disables it
+func (c *PubSubClient) SetTracer(tracer Tracer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trace = tracer
+}
+
+func (c *PubSubClient) tracer() Tracer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.trace == nil {
+		return NopTracer{}
+	}
+	return c.trace
+}
+
+// Done is closed when the connection is gone
+func (c *PubSubClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason the connection ended, or nil while it is open
+func (c *PubSubClient) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *PubSubClient) shutdown(reason error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.err = reason
+		subs := c.subs
+		c.subs = make(map[uint64]*Subscription)
+		c.mu.Unlock()
+
+		close(c.done)
+		c.conn.Close()
+		for _, sub := range subs {
+			sub.finish()
+		}
+	})
+}
+
+func (c *PubSubClient) readLoop() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				err = ErrPubSubClosed
+			}
+			c.shutdown(err)
+			return
+		}
+		msg := pubSubMessage{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.logger().WithFields(Fields{"func": "readLoop"}).Error(err)
+			continue
+		}
+		if msg.ID != nil {
+			c.handleReply(msg)
+			continue
+		}
+		if strings.HasSuffix(msg.Method, "Notification") {
+			c.handleNotification(msg)
+		}
+	}
+}
+
+func (c *PubSubClient) handleReply(msg pubSubMessage) {
+	c.mu.Lock()
+	call, ok := c.pending[*msg.ID]
+	delete(c.pending, *msg.ID)
+	drop := false
+	if ok && call.sub != nil && msg.Error.Code == 0 {
+		if err := json.Unmarshal(msg.Result, &call.sub.id); err == nil {
+			if drop = call.abandoned; !drop {
+				c.subs[call.sub.id] = call.sub
+			}
+		}
+	}
+	c.mu.Unlock()
+	if drop {
+		// the read loop must keep reading for the reply of the unsubscribe
+		go c.drop(call.sub)
+	}
+	if ok {
+		call.reply <- msg
+	}
+}
+
+func (c *PubSubClient) handleNotification(msg pubSubMessage) {
+	c.mu.Lock()
+	sub, ok := c.subs[msg.Params.Subscription]
+	// signatureSubscribe is removed by the server after its single notification
+	if ok && msg.Method == "signatureNotification" {
+		delete(c.subs, sub.id)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	_, span := startSpan(context.Background(), c.tracer(), spanPrefix+msg.Method)
+	span.SetAttribute(AttrRPCMethod, msg.Method)
+	span.SetAttribute(AttrSubscriptionID, msg.Params.Subscription)
+	defer span.End()
+	select {
+	case sub.raw <- msg.Params.Result:
+	case <-sub.done:
+	case <-c.done:
+	}
+	if msg.Method == "signatureNotification" {
+		sub.finish()
+	}
+}
+
+// call sends a request and waits for its reply. When sub is not nil the reply
+// is a subscription ID and sub is registered under it.
+func (c *PubSubClient) call(ctx context.Context, method string, params []interface{}, sub *Subscription) (result json.RawMessage, err error) {
+	id := atomic.AddUint64(&c.nextID, 1)
+	ctx, span := startSpan(ctx, c.tracer(), spanPrefix+method)
+	span.SetAttribute(AttrRPCMethod, method)
+	span.SetAttribute(AttrRPCRequestID, id)
+	defer func() {
+		endSpan(span, RPCResponseError{}, err)
+	}()
+	call := &pendingCall{reply: make(chan pubSubMessage, 1), sub: sub}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	c.pending[id] = call
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		if !call.abandoned {
+			delete(c.pending, id)
+		}
+		c.mu.Unlock()
+	}()
+
+	rpcReq := RPCRequest{Version: RequestVersion, ID: id, Method: method, Params: params}
+	c.writeMu.Lock()
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+	}
+	err = c.conn.WriteJSON(rpcReq)
+	// a deadline left in place would fail the next write once it passes
+	c.conn.SetWriteDeadline(time.Time{})
+	c.writeMu.Unlock()
+	if err != nil {
+		c.logger().WithFields(Fields{"func": "call", "method": method}).Error(err)
+		return nil, contextError(ctx, err)
+	}
+
+	select {
+	case msg := <-call.reply:
+		if msg.Error.Code != 0 {
+			return nil, msg.Error.Err()
+		}
+		return msg.Result, nil
+	case <-ctx.Done():
+		if sub != nil {
+			c.abandon(id, call)
+		}
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, c.Err()
+	}
+}
+
+// abandon undoes the subscribe call id whose caller stopped waiting. A sub
+// already registered is removed and cancelled on the node, one still waiting
+// for its reply is cancelled by handleReply when the reply arrives.
+func (c *PubSubClient) abandon(id uint64, call *pendingCall) {
+	c.mu.Lock()
+	_, waiting := c.pending[id]
+	call.abandoned = waiting
+	registered := !waiting && c.subs[call.sub.id] == call.sub
+	if registered {
+		delete(c.subs, call.sub.id)
+	}
+	c.mu.Unlock()
+	call.sub.finish()
+	if registered {
+		go c.drop(call.sub)
+	}
+}
+
+// drop cancels sub on the node, on a best effort basis since no caller waits for it
+func (c *PubSubClient) drop(sub *Subscription) {
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+	sub.finish()
+	unsubMethod := unsubscribeMethod(sub.method)
+	if _, err := c.call(ctx, unsubMethod, []interface{}{sub.id}, nil); err != nil {
+		c.logger().WithFields(Fields{"func": "drop", "method": unsubMethod}).Error(err)
+	}
+}
+
+func unsubscribeMethod(subscribeMethod string) string {
+	return strings.Replace(subscribeMethod, "Subscribe", "Unsubscribe", 1)
+}
+
+func (c *PubSubClient) subscribe(ctx context.Context, method string, params []interface{}) (*Subscription, error) {
+	sub := &Subscription{
+		client: c,
+		method: method,
+		raw:    make(chan json.RawMessage, NotificationBufferSize),
+		done:   make(chan struct{}),
+	}
+	if _, err := c.call(ctx, method, params, sub); err != nil {
+		c.logger().WithFields(Fields{"func": "subscribe", "method": method}).Error(err)
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Subscription is the untyped part shared by every subscription kind
+type Subscription struct {
+	client *PubSubClient
+	method string
+	id     uint64
+	raw    chan json.RawMessage
+
+	done       chan struct{}
+	finishOnce sync.Once
+}
+
+// ID returns the subscription ID assigned by the node
+func (s *Subscription) ID() uint64 {
+	return s.id
+}
+
+// Unsubscribe cancels the subscription on the node and closes its channel
+func (s *Subscription) Unsubscribe(ctx context.Context) error {
+	c := s.client
+	c.mu.Lock()
+	_, active := c.subs[s.id]
+	delete(c.subs, s.id)
+	c.mu.Unlock()
+	s.finish()
+	if !active {
+		return nil
+	}
+
+	unsubMethod := unsubscribeMethod(s.method)
+	result, err := c.call(ctx, unsubMethod, []interface{}{s.id}, nil)
+	if err != nil {
+		c.logger().WithFields(Fields{"func": "Unsubscribe", "method": unsubMethod}).Error(err)
+		return err
+	}
+	ok := false
+	if err := json.Unmarshal(result, &ok); err != nil || !ok {
+		return ErrUnsubscribeFailed
+	}
+	return nil
+}
+
+func (s *Subscription) finish() {
+	s.finishOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// forward decodes raw notifications with decode until the subscription ends,
+// then runs closeTyped so the typed channel is closed exactly once
+func (s *Subscription) forward(decode func(json.RawMessage) bool, closeTyped func()) {
+	defer closeTyped()
+	for {
+		select {
+		case raw := <-s.raw:
+			if !decode(raw) {
+				return
+			}
+		case <-s.done:
+			// deliver what was queued before the end
+			for {
+				select {
+				case raw := <-s.raw:
+					if !decode(raw) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Account subscription
+
+type AccountSubscribeConfig struct {
+	Commitment CommitmentVal `json:"commitment,omitempty"`
+	Encoding   EncodeMethod  `json:"encoding,omitempty"`
+}
+
+type AccountNotification struct {
+	Context RPCContext       `json:"context"`
+	Value   AccountInfoValue `json:"value"`
+}
+
+type AccountSubscription struct {
+	*Subscription
+	C <-chan AccountNotification
+}
+
+// AccountSubscribe watches one account. Encoding must be a binary encoding
+// since notifications decode into AccountInfoValue.
+func (c *PubSubClient) AccountSubscribe(ctx context.Context, base58Pubkey string, config *AccountSubscribeConfig) (*AccountSubscription, error) {
+	if len(base58Pubkey) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	params := []interface{}{base58Pubkey}
+	if config != nil {
+		params = append(params, *config)
+	}
+	sub, err := c.subscribe(ctx, "accountSubscribe", params)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan AccountNotification)
+	go sub.forward(func(raw json.RawMessage) bool {
+		n := AccountNotification{}
+		if err := json.Unmarshal(raw, &n); err != nil {
+			c.logger().WithFields(Fields{"func": "AccountSubscribe"}).Error(err)
+			return true
+		}
+		select {
+		case ch <- n:
+			return true
+		case <-sub.done:
+			return false
+		}
+	}, func() { close(ch) })
+	return &AccountSubscription{Subscription: sub, C: ch}, nil
+}
+
+// Program subscription
+
+type ProgramSubscribeConfig struct {
+	Commitment CommitmentVal `json:"commitment,omitempty"`
+	Encoding   EncodeMethod  `json:"encoding,omitempty"`
+	Filters    []interface{} `json:"filters,omitempty"`
+}
+
+type ProgramAccount struct {
+	PubKey  string           `json:"pubkey"`
+	Account AccountInfoValue `json:"account"`
+}
+
+type ProgramNotification struct {
+	Context RPCContext     `json:"context"`
+	Value   ProgramAccount `json:"value"`
+}
+
+type ProgramSubscription struct {
+	*Subscription
+	C <-chan ProgramNotification
+}
+
+func (c *PubSubClient) ProgramSubscribe(ctx context.Context, base58ProgramID string, config *ProgramSubscribeConfig) (*ProgramSubscription, error) {
+	if len(base58ProgramID) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	params := []interface{}{base58ProgramID}
+	if config != nil {
+		params = append(params, *config)
+	}
+	sub, err := c.subscribe(ctx, "programSubscribe", params)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan ProgramNotification)
+	go sub.forward(func(raw json.RawMessage) bool {
+		n := ProgramNotification{}
+		if err := json.Unmarshal(raw, &n); err != nil {
+			c.logger().WithFields(Fields{"func": "ProgramSubscribe"}).Error(err)
+			return true
+		}
+		select {
+		case ch <- n:
+			return true
+		case <-sub.done:
+			return false
+		}
+	}, func() { close(ch) })
+	return &ProgramSubscription{Subscription: sub, C: ch}, nil
+}
+
+// Signature subscription
+
+type SignatureNotification struct {
+	Context RPCContext `json:"context"`
+	Value   struct {
+		Err interface{} `json:"err"` // null when the transaction succeeded
+	} `json:"value"`
+}
+
+type SignatureSubscription struct {
+	*Subscription
+	C <-chan SignatureNotification
+}
+
+// SignatureSubscribe waits for one transaction. The node ends the subscription
+// after the first notification, and C is closed after it is delivered.
+func (c *PubSubClient) SignatureSubscribe(ctx context.Context, base58Sig string, commitment *CommitmentConfig) (*SignatureSubscription, error) {
+	if len(base58Sig) == 0 {
+		return nil, ErrInvalidFuncParameter
+	}
+	params := []interface{}{base58Sig}
+	if commitment != nil && len(commitment.Commitment) > 0 {
+		params = append(params, *commitment)
+	}
+	sub, err := c.subscribe(ctx, "signatureSubscribe", params)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan SignatureNotification, 1)
+	go sub.forward(func(raw json.RawMessage) bool {
+		n := SignatureNotification{}
+		if err := json.Unmarshal(raw, &n); err != nil {
+			c.logger().WithFields(Fields{"func": "SignatureSubscribe"}).Error(err)
+			return true
+		}
+		ch <- n
+		return true
+	}, func() { close(ch) })
+	return &SignatureSubscription{Subscription: sub, C: ch}, nil
+}
+
+// Slot subscription
+
+type SlotNotification struct {
+	Parent uint64 `json:"parent"`
+	Root   uint64 `json:"root"`
+	Slot   uint64 `json:"slot"`
+}
+
+type SlotSubscription struct {
+	*Subscription
+	C <-chan SlotNotification
+}
+
+func (c *PubSubClient) SlotSubscribe(ctx context.Context) (*SlotSubscription, error) {
+	sub, err := c.subscribe(ctx, "slotSubscribe", nil)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan SlotNotification)
+	go sub.forward(func(raw json.RawMessage) bool {
+		n := SlotNotification{}
+		if err := json.Unmarshal(raw, &n); err != nil {
+			c.logger().WithFields(Fields{"func": "SlotSubscribe"}).Error(err)
+			return true
+		}
+		select {
+		case ch <- n:
+			return true
+		case <-sub.done:
+			return false
+		}
+	}, func() { close(ch) })
+	return &SlotSubscription{Subscription: sub, C: ch}, nil
+}
+
+// Root subscription
+
+type RootSubscription struct {
+	*Subscription
+	C <-chan uint64 // new root slot
+}
+
+func (c *PubSubClient) RootSubscribe(ctx context.Context) (*RootSubscription, error) {
+	sub, err := c.subscribe(ctx, "rootSubscribe", nil)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan uint64)
+	go sub.forward(func(raw json.RawMessage) bool {
+		var root uint64
+		if err := json.Unmarshal(raw, &root); err != nil {
+			c.logger().WithFields(Fields{"func": "RootSubscribe"}).Error(err)
+			return true
+		}
+		select {
+		case ch <- root:
+			return true
+		case <-sub.done:
+			return false
+		}
+	}, func() { close(ch) })
+	return &RootSubscription{Subscription: sub, C: ch}, nil
+}
+
+// Logs subscription
+
+type LogsNotification struct {
+	Context RPCContext `json:"context"`
+	Value   struct {
+		Signature string      `json:"signature"`
+		Err       interface{} `json:"err"`
+		Logs      []string    `json:"logs"`
+	} `json:"value"`
+}
+
+type LogsSubscription struct {
+	*Subscription
+	C <-chan LogsNotification
+}
+
+// LogsSubscribe takes LogsFilterAll, LogsFilterAllWithVotes or LogsMentions(pubkey)
+func (c *PubSubClient) LogsSubscribe(ctx context.Context, filter interface{}, commitment *CommitmentConfig) (*LogsSubscription, error) {
+	if filter == nil {
+		return nil, ErrInvalidFuncParameter
+	}
+	params := []interface{}{filter}
+	if commitment != nil && len(commitment.Commitment) > 0 {
+		params = append(params, *commitment)
+	}
+	sub, err := c.subscribe(ctx, "logsSubscribe", params)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan LogsNotification)
+	go sub.forward(func(raw json.RawMessage) bool {
+		n := LogsNotification{}
+		if err := json.Unmarshal(raw, &n); err != nil {
+			c.logger().WithFields(Fields{"func": "LogsSubscribe"}).Error(err)
+			return true
+		}
+		select {
+		case ch <- n:
+			return true
+		case <-sub.done:
+			return false
+		}
+	}, func() { close(ch) })
+	return &LogsSubscription{Subscription: sub, C: ch}, nil
+}
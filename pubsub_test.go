@@ -0,0 +1,206 @@
+package solanarpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// pubSubStandIn answers every *Subscribe with a new subscription ID followed
+// by one notification, and every *Unsubscribe with true
+func pubSubStandIn(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		subID := 100
+		for {
+			rpcReq := RPCRequest{}
+			if err := conn.ReadJSON(&rpcReq); err != nil {
+				return
+			}
+			if strings.HasSuffix(rpcReq.Method, "Unsubscribe") {
+				conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","result":true,"id":%d}`, rpcReq.ID)))
+				continue
+			}
+			subID++
+			conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","result":%d,"id":%d}`, subID, rpcReq.ID)))
+			notification := strings.Replace(rpcReq.Method, "Subscribe", "Notification", 1)
+			var result string
+			switch rpcReq.Method {
+			case "accountSubscribe":
+				result = `{"context":{"slot":5199307},"value":{"data":["11116bv5nS2h3y12kD1yUKeMZvGcKLSjQgX6BeV7u1FrjeJcKfsHRTPuR3oZ1EioKtYGiYxpxMG5vpbZLsbcBYBEmZZcMKaSoGx9JZeAuWf","base58"],"executable":false,"lamports":33594,"owner":"11111111111111111111111111111111","rentEpoch":635}}`
+			case "slotSubscribe":
+				result = `{"parent":75,"root":44,"slot":76}`
+			case "rootSubscribe":
+				result = `42`
+			case "signatureSubscribe":
+				result = `{"context":{"slot":5207624},"value":{"err":null}}`
+			case "logsSubscribe":
+				result = `{"context":{"slot":5208469},"value":{"signature":"5h6xBEauJ3PK6SWCZ1PGjBvj8vDdWG3KpwATGy1ARAXFSDwt8GFXM7W5Ncn16wmqokgpiKRLuS83KUxyZyv2sUYv","err":null,"logs":["BPF program 83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri success"]}}`
+			default:
+				continue
+			}
+			conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","method":"%s","params":{"result":%s,"subscription":%d}}`, notification, result, subID)))
+		}
+	}))
+}
+
+func TestPubSubSubscriptions(t *testing.T) {
+	srv := pubSubStandIn(t)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := DialPubSub(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	acct, err := client.AccountSubscribe(ctx, "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", &AccountSubscribeConfig{Encoding: Base58, Commitment: Finalized})
+	assert.NoError(t, err)
+	n := <-acct.C
+	assert.Equal(t, uint64(5199307), n.Context.Slot)
+	assert.Equal(t, uint64(33594), n.Value.Lamports)
+	assert.NoError(t, acct.Unsubscribe(ctx))
+	_, open := <-acct.C
+	assert.False(t, open, "channel should be closed after unsubscribe")
+
+	slot, err := client.SlotSubscribe(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, SlotNotification{Parent: 75, Root: 44, Slot: 76}, <-slot.C)
+
+	root, err := client.RootSubscribe(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), <-root.C)
+
+	sig, err := client.SignatureSubscribe(ctx, "2EBVM6cB8vAAD93Ktr6Vd8p67XPbQzCJX47MpReuiCXJAtcjaxpvWpcg9Ege1Nr5Tk3a2GFrByT7WPBjdsTycY9b", nil)
+	assert.NoError(t, err)
+	sn := <-sig.C
+	assert.Nil(t, sn.Value.Err)
+	_, open = <-sig.C
+	assert.False(t, open, "signature subscription ends after one notification")
+
+	logs, err := client.LogsSubscribe(ctx, LogsMentions("83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri"), nil)
+	assert.NoError(t, err)
+	ln := <-logs.C
+	assert.Len(t, ln.Value.Logs, 1)
+
+	assert.NoError(t, client.Close())
+	_, open = <-slot.C
+	assert.False(t, open, "close should end every subscription")
+	<-client.Done()
+	assert.Equal(t, ErrPubSubClosed, client.Err())
+	_, err = client.RootSubscribe(ctx)
+	assert.Equal(t, ErrPubSubClosed, err)
+}
+
+func TestPubSubSubscribeError(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, _ := upgrader.Upgrade(w, req, nil)
+		defer conn.Close()
+		rpcReq := RPCRequest{}
+		conn.ReadJSON(&rpcReq)
+		resp := RPCResponse{Version: RequestVersion, ID: rpcReq.ID, Error: RPCResponseError{Code: -32602, Message: "Invalid Request: Invalid pubkey provided"}}
+		conn.WriteJSON(resp)
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	client, err := DialPubSub(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"))
+	assert.NoError(t, err)
+	defer client.Close()
+	_, err = client.ProgramSubscribe(ctx, "bad", nil)
+	assert.EqualError(t, err, "Invalid Request: Invalid pubkey provided")
+}
+
+func TestPubSubWriteDeadlineReset(t *testing.T) {
+	srv := pubSubStandIn(t)
+	defer srv.Close()
+	client, err := DialPubSub(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.RootSubscribe(ctx)
+	assert.NoError(t, err)
+	<-ctx.Done()
+
+	// the expired deadline of the previous call must not fail this one
+	_, err = client.SlotSubscribe(context.Background())
+	assert.NoError(t, err)
+}
+
+// A subscription whose caller gave up before the reply must not stall the
+// read loop with notifications nobody reads
+func TestPubSubSubscribeCanceled(t *testing.T) {
+	canceled := make(chan struct{})
+	unsubscribed := make(chan []interface{}, 1)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, _ := upgrader.Upgrade(w, req, nil)
+		defer conn.Close()
+		for {
+			rpcReq := RPCRequest{}
+			if err := conn.ReadJSON(&rpcReq); err != nil {
+				return
+			}
+			switch rpcReq.Method {
+			case "rootSubscribe":
+				<-canceled
+				conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","result":7,"id":%d}`, rpcReq.ID)))
+				for i := 0; i < 2*NotificationBufferSize; i++ {
+					conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"rootNotification","params":{"result":42,"subscription":7}}`))
+				}
+			case "rootUnsubscribe":
+				unsubscribed <- rpcReq.Params
+				conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","result":true,"id":%d}`, rpcReq.ID)))
+			case "slotSubscribe":
+				conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"jsonrpc":"2.0","result":8,"id":%d}`, rpcReq.ID)))
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"slotNotification","params":{"result":{"parent":75,"root":44,"slot":76},"subscription":8}}`))
+			}
+		}
+	}))
+	defer srv.Close()
+	client, err := DialPubSub(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	_, err = client.RootSubscribe(ctx)
+	assert.Equal(t, context.Canceled, err)
+	close(canceled)
+
+	select {
+	case params := <-unsubscribed:
+		assert.Equal(t, []interface{}{7.0}, params)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the late subscription was not cancelled")
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	slots, err := client.SlotSubscribe(ctx)
+	assert.NoError(t, err)
+	select {
+	case n := <-slots.C:
+		assert.Equal(t, uint64(76), n.Slot)
+	case <-ctx.Done():
+		t.Fatal("the read loop is stalled")
+	}
+}
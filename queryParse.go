@@ -2,101 +2,35 @@ package solanarpc
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
-	"strconv"
 	"strings"
-
-	log "github.com/sirupsen/logrus"
 )
 
-func ParseAccountInfoResponse(resp *RPCResponse) (*AccountInfoValue, error) {
-	// check Error Code
-	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseAccountInfoResponse"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
-	}
-	result := new(RPCResult)
-	json.Unmarshal(resp.Result, result)
-
-	if strings.EqualFold(string(result.Value), "null") {
-		log.WithFields(log.Fields{"func": "ParseAccountInfoResponse"}).Error(ErrAccountNotExist)
-		return nil, ErrAccountNotExist
-	}
-	value := new(AccountInfoValue)
-	json.Unmarshal(result.Value, value)
-
-	return value, nil
-}
-
-func ParseBalanceResponse(resp *RPCResponse) (uint64, error) {
-	if resp.Error.Code != 0 {
-		return 0, errors.New(resp.Error.Message)
-	}
-
-	result := new(RPCResult)
-	err := json.Unmarshal(resp.Result, result)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseBalanceResponse"}).Error(err)
-		return 0, err
-	}
-	u, err := strconv.ParseUint(string(result.Value), 10, 64)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseBalanceResponse"}).Error(err)
-		return 0, err
-	}
-	log.WithFields(log.Fields{"func": "ParseBalanceResponse"}).Debug(string(result.Value))
-	return u, nil
-}
+// The parsers of the methods in methods.json are generated into queryParse_gen.go.
 
-func ParseBlockCommitmentResponse(resp *RPCResponse) (*BlockCommitment, error) {
+// ParseContextResult parses the {context, value} result of resp into value,
+// which must be a pointer, and returns the context. A null value is ErrNullResult.
+func ParseContextResult(resp *RPCResponse, value interface{}) (RPCContext, error) {
 	if resp.Error.Code != 0 {
-		return nil, errors.New(resp.Error.Message)
-	}
-	commitment := new(BlockCommitment)
-	err := json.Unmarshal(resp.Result, commitment)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseBlockCommitment"}).Error(err)
-		return nil, err
+		return RPCContext{}, resp.Error.Err()
 	}
-	return commitment, nil
-}
-
-func ParseBlockTimeResponse(resp *RPCResponse) (uint64, error) {
-	if strings.EqualFold(string(resp.Result), "null") {
-		log.WithFields(log.Fields{"func": "ParseBlockTime"}).Error(ErrTimeStampNotAvailable)
-		return 0, ErrTimeStampNotAvailable
+	if isNullJSON(resp.Result) {
+		return RPCContext{}, ErrNullResult
 	}
-	timestamp, err := strconv.ParseUint(string(resp.Result), 10, 64)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseBlockTime"}).Error(err)
-		return 0, err
-	}
-	return timestamp, nil
-}
-
-func ParseClusterNodesResponse(resp *RPCResponse) ([]ContactInfo, error) {
-	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseClusterNodes"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
-	}
-	if strings.EqualFold(string(resp.Result), "null") {
-		log.WithFields(log.Fields{"func": "ParseClusterNodes"}).Error(ErrTimeStampNotAvailable)
-		return nil, ErrSpecifiedBlockNotConfirmed
+	result := ContextResult{Value: value}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return result.Context, err
 	}
-	nodes := []ContactInfo{}
-	if err := json.Unmarshal(resp.Result, &nodes); err != nil {
-		log.WithFields(log.Fields{"func": "ParseClusterNodes"}).Error(err)
-		return nil, err
+	if result.Value == nil {
+		return result.Context, ErrNullResult
 	}
-	return nodes, nil
+	return result.Context, nil
 }
 
 func ParseConfirmedBlockResponse(resp *RPCResponse) (*ConfirmedBlock, error) {
 	// check Error Code
 	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseConfirmedBlock"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
+		parseLog().WithFields(Fields{"func": "ParseConfirmedBlock"}).Error(resp.Error.Err())
+		return nil, resp.Error.Err()
 	}
 	if strings.EqualFold(string(resp.Result), "null") {
 		return nil, ErrSpecifiedBlockNotConfirmed
@@ -112,8 +46,8 @@ func ParseConfirmedBlockResponse(resp *RPCResponse) (*ConfirmedBlock, error) {
 func ParseConfirmedBlocks(resp *RPCResponse) ([]uint64, error) {
 	// check Error Code
 	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseConfirmedBlocks"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
+		parseLog().WithFields(Fields{"func": "ParseConfirmedBlocks"}).Error(resp.Error.Err())
+		return nil, resp.Error.Err()
 	}
 	blocks := []uint64{}
 	json.Unmarshal(resp.Result, &blocks)
@@ -123,89 +57,50 @@ func ParseConfirmedBlocks(resp *RPCResponse) ([]uint64, error) {
 func ParseConfimedBlocksLimit(resp *RPCResponse) ([]uint64, error) {
 	// check Error Code
 	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseConfimedBlocksLimit"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
+		parseLog().WithFields(Fields{"func": "ParseConfimedBlocksLimit"}).Error(resp.Error.Err())
+		return nil, resp.Error.Err()
 	}
 	blocks := []uint64{}
 	json.Unmarshal(resp.Result, &blocks)
 	return blocks, nil
 }
 
-func ParseConfirmedSignaturesForAddress2(resp *RPCResponse) ([]ConfirmedSignaturesForAddress2, error) {
-	// check Error Code
-	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseConfirmedSignaturesForAddress2"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
-	}
-	sig := []ConfirmedSignaturesForAddress2{}
-	err := json.Unmarshal(resp.Result, &sig)
-	fmt.Println(sig)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseConfirmedSignaturesForAddress2"}).Error(err)
-		return nil, err
-	}
-	return sig, nil
-}
-
-func ParseTokenSupply(resp *RPCResponse) (*TokenValue, error) {
-	// check Error Code
-	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseTokenSupply"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
-	}
-	result := new(RPCResult)
-	err := json.Unmarshal(resp.Result, result)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseTokenSupply"}).Error(err)
-		return nil, err
-	}
-	value := new(TokenValue)
-	err = json.Unmarshal(result.Value, value)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseTokenSupply"}).Error(err)
-		return nil, err
-	}
-	return value, nil
-}
-
-func ParseTokenAccountBalance(resp *RPCResponse) (*TokenValue, error) {
-	// check Error Code
-	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseTokenAccountBalance"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
-	}
-	result := new(RPCResult)
-	err := json.Unmarshal(resp.Result, result)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseTokenAccountBalance"}).Error(err)
-		return nil, err
-	}
-	value := new(TokenValue)
-	err = json.Unmarshal(result.Value, value)
+func ParseTokenAccountsByDelegate(resp *RPCResponse) ([]TokenAccountsByDelegateValue, RPCContext, error) {
+	value := []TokenAccountsByDelegateValue{}
+	rpcCtx, err := ParseContextResult(resp, &value)
 	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseTokenAccountBalance"}).Error(err)
-		return nil, err
+		parseLog().WithFields(Fields{"func": "ParseTokenAccountsByDelegate"}).Error(err)
+		return nil, rpcCtx, err
 	}
-	return value, nil
+	return value, rpcCtx, nil
 }
 
-func ParseTokenAccountsByDelegate(resp *RPCResponse) ([]TokenAccountsByDelegateValue, error) {
-	// check Error Code
-	if resp.Error.Code != 0 {
-		log.WithFields(log.Fields{"func": "ParseTokenAccountsByDelegate"}).Error(errors.New(resp.Error.Message))
-		return nil, errors.New(resp.Error.Message)
-	}
-	result := new(RPCResult)
-	err := json.Unmarshal(resp.Result, result)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseTokenAccountsByDelegate"}).Error(err)
-		return nil, err
-	}
-	value := []TokenAccountsByDelegateValue{}
-	err = json.Unmarshal(result.Value, &value)
-	if err != nil {
-		log.WithFields(log.Fields{"func": "ParseTokenAccountsByDelegate"}).Error(err)
-		return nil, err
-	}
-	return value, nil
+// ParseSignatureStatuses parses the responses of GetSignatureStatuses for
+// signatures into one status per signature, nil for unknown ones. The context
+// returned is the one with the lowest slot. A response without one status per
+// signature of its request fails with ErrStatusCountMismatch.
+func ParseSignatureStatuses(signatures []string, resps ...*RPCResponse) ([]*SignatureStatus, RPCContext, error) {
+	chunks := signatureChunks(signatures)
+	if len(resps) != len(chunks) {
+		parseLog().WithFields(Fields{"func": "ParseSignatureStatuses"}).Error(ErrStatusCountMismatch)
+		return nil, RPCContext{}, ErrStatusCountMismatch
+	}
+	statuses := make([]*SignatureStatus, 0, len(signatures))
+	rpcCtx := RPCContext{}
+	for i, resp := range resps {
+		value := []*SignatureStatus{}
+		chunkCtx, err := ParseContextResult(resp, &value)
+		if err == nil && len(value) != len(chunks[i]) {
+			err = ErrStatusCountMismatch
+		}
+		if err != nil {
+			parseLog().WithFields(Fields{"func": "ParseSignatureStatuses"}).Error(err)
+			return nil, chunkCtx, err
+		}
+		if i == 0 || chunkCtx.Slot < rpcCtx.Slot {
+			rpcCtx = chunkCtx
+		}
+		statuses = append(statuses, value...)
+	}
+	return statuses, rpcCtx, nil
 }
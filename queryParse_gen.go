@@ -0,0 +1,138 @@
+// Code generated by cmd/genapi from methods.json. DO NOT EDIT.
+
+package solanarpc
+
+import "encoding/json"
+
+// ParseAccountInfoResponse parses the response of GetAccountInfo and returns its context too
+func ParseAccountInfoResponse(resp *RPCResponse) (*AccountInfoValue, RPCContext, error) {
+	value := new(AccountInfoValue)
+	rpcCtx, err := ParseContextResult(resp, value)
+	if err == ErrNullResult {
+		err = ErrAccountNotExist
+	}
+	if err != nil {
+		parseLog().WithFields(Fields{"func": "ParseAccountInfoResponse"}).Error(err)
+		return nil, rpcCtx, err
+	}
+	return value, rpcCtx, nil
+}
+
+// ParseBalanceResponse parses the response of GetBalance and returns its context too
+func ParseBalanceResponse(resp *RPCResponse) (uint64, RPCContext, error) {
+	var value uint64
+	rpcCtx, err := ParseContextResult(resp, &value)
+	if err != nil {
+		parseLog().WithFields(Fields{"func": "ParseBalanceResponse"}).Error(err)
+		return 0, rpcCtx, err
+	}
+	return value, rpcCtx, nil
+}
+
+// ParseBlockCommitmentResponse parses the response of GetBlockCommitment
+func ParseBlockCommitmentResponse(resp *RPCResponse) (*BlockCommitment, error) {
+	if resp.Error.Code != 0 {
+		parseLog().WithFields(Fields{"func": "ParseBlockCommitmentResponse"}).Error(resp.Error.Err())
+		return nil, resp.Error.Err()
+	}
+	if isNullJSON(resp.Result) {
+		parseLog().WithFields(Fields{"func": "ParseBlockCommitmentResponse"}).Error(ErrNullResult)
+		return nil, ErrNullResult
+	}
+	value := new(BlockCommitment)
+	if err := json.Unmarshal(resp.Result, value); err != nil {
+		parseLog().WithFields(Fields{"func": "ParseBlockCommitmentResponse"}).Error(err)
+		return nil, err
+	}
+	return value, nil
+}
+
+// ParseBlockTimeResponse parses the response of GetBlockTime
+func ParseBlockTimeResponse(resp *RPCResponse) (uint64, error) {
+	if resp.Error.Code != 0 {
+		parseLog().WithFields(Fields{"func": "ParseBlockTimeResponse"}).Error(resp.Error.Err())
+		return 0, resp.Error.Err()
+	}
+	if isNullJSON(resp.Result) {
+		parseLog().WithFields(Fields{"func": "ParseBlockTimeResponse"}).Error(ErrTimeStampNotAvailable)
+		return 0, ErrTimeStampNotAvailable
+	}
+	var value uint64
+	if err := json.Unmarshal(resp.Result, &value); err != nil {
+		parseLog().WithFields(Fields{"func": "ParseBlockTimeResponse"}).Error(err)
+		return 0, err
+	}
+	return value, nil
+}
+
+// ParseClusterNodesResponse parses the response of GetClusterNodes
+func ParseClusterNodesResponse(resp *RPCResponse) ([]ContactInfo, error) {
+	if resp.Error.Code != 0 {
+		parseLog().WithFields(Fields{"func": "ParseClusterNodesResponse"}).Error(resp.Error.Err())
+		return nil, resp.Error.Err()
+	}
+	if isNullJSON(resp.Result) {
+		parseLog().WithFields(Fields{"func": "ParseClusterNodesResponse"}).Error(ErrSpecifiedBlockNotConfirmed)
+		return nil, ErrSpecifiedBlockNotConfirmed
+	}
+	var value []ContactInfo
+	if err := json.Unmarshal(resp.Result, &value); err != nil {
+		parseLog().WithFields(Fields{"func": "ParseClusterNodesResponse"}).Error(err)
+		return nil, err
+	}
+	return value, nil
+}
+
+// ParseConfirmedSignaturesForAddress2 parses the response of GetConfirmedSignaturesForAddress2
+func ParseConfirmedSignaturesForAddress2(resp *RPCResponse) ([]ConfirmedSignaturesForAddress2, error) {
+	if resp.Error.Code != 0 {
+		parseLog().WithFields(Fields{"func": "ParseConfirmedSignaturesForAddress2"}).Error(resp.Error.Err())
+		return nil, resp.Error.Err()
+	}
+	var value []ConfirmedSignaturesForAddress2
+	if err := json.Unmarshal(resp.Result, &value); err != nil {
+		parseLog().WithFields(Fields{"func": "ParseConfirmedSignaturesForAddress2"}).Error(err)
+		return nil, err
+	}
+	return value, nil
+}
+
+// ParseTokenSupply parses the response of GetTokenSupply and returns its context too
+func ParseTokenSupply(resp *RPCResponse) (*TokenValue, RPCContext, error) {
+	value := new(TokenValue)
+	rpcCtx, err := ParseContextResult(resp, value)
+	if err != nil {
+		parseLog().WithFields(Fields{"func": "ParseTokenSupply"}).Error(err)
+		return nil, rpcCtx, err
+	}
+	return value, rpcCtx, nil
+}
+
+// ParseTokenAccountBalance parses the response of GetTokenAccountBalance and returns its context too
+func ParseTokenAccountBalance(resp *RPCResponse) (*TokenValue, RPCContext, error) {
+	value := new(TokenValue)
+	rpcCtx, err := ParseContextResult(resp, value)
+	if err != nil {
+		parseLog().WithFields(Fields{"func": "ParseTokenAccountBalance"}).Error(err)
+		return nil, rpcCtx, err
+	}
+	return value, rpcCtx, nil
+}
+
+// ParseTransactionResponse parses the response of GetTransaction
+func ParseTransactionResponse(resp *RPCResponse) (*ConfirmedTransaction, error) {
+	if resp.Error.Code != 0 {
+		parseLog().WithFields(Fields{"func": "ParseTransactionResponse"}).Error(resp.Error.Err())
+		return nil, resp.Error.Err()
+	}
+	if isNullJSON(resp.Result) {
+		parseLog().WithFields(Fields{"func": "ParseTransactionResponse"}).Error(ErrTransactionNotFound)
+		return nil, ErrTransactionNotFound
+	}
+	value := new(ConfirmedTransaction)
+	if err := json.Unmarshal(resp.Result, value); err != nil {
+		parseLog().WithFields(Fields{"func": "ParseTransactionResponse"}).Error(err)
+		return nil, err
+	}
+	return value, nil
+}
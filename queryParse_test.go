@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"solanarpc/internal/fixtures"
 )
 
 type RPCResultTestSuite struct {
@@ -90,52 +91,52 @@ type RPCResultTestSuite struct {
 func (s *RPCResultTestSuite) SetupTest() {
 	// TestParseAccountInfoResponse
 	resp := new(RPCResponse)
-	err := json.Unmarshal([]byte(testResultAccountExsitBase58), resp)
+	err := json.Unmarshal([]byte(fixtures.AccountExsitBase58), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.AccountInfoValueResponse01 = resp
 	s.AccountInfoValueResult01 = AccountInfoValue{Lamports: 1000000000,
 		Owner: "11111111111111111111111111111111", Executable: false, RentEpoch: 2, Data: []string{"11116bv5nS2h3y12kD1yUKeMZvGcKLSjQgX6BeV7u1FrjeJcKfsHRTPuR3oZ1EioKtYGiYxpxMG5vpbZLsbcBYBEmZZcMKaSoGx9JZeAuWf",
 			"base58"}}
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultAccountNotExist), resp)
+	err = json.Unmarshal([]byte(fixtures.AccountNotExist), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.AccountInfoValueResponse02 = resp
 	s.AccountInfoValueResult02 = ErrAccountNotExist
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultAccountError), resp)
+	err = json.Unmarshal([]byte(fixtures.AccountError), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.AccountInfoValueResponse03 = resp
-	s.AccountInfoValueResult03 = errors.New("Invalid param: WrongSize")
+	s.AccountInfoValueResult03 = &RPCError{Code: ErrCodeInvalidParams, Message: "Invalid param: WrongSize"}
 	// TestParseBalanceResponse
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultBalance01), resp)
+	err = json.Unmarshal([]byte(fixtures.Balance01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.BalanceResponse01 = resp
 	s.BalanceResult01 = 27020041285
 	// TestParseBlockCommitment
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultBlockCommitment01), resp)
+	err = json.Unmarshal([]byte(fixtures.BlockCommitment01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.BlockCommitmentResponse01 = resp
 	s.BlockCommitmentResult01 = BlockCommitment{Commitment: []uint64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 32}, TotalStake: 42}
 	// TestParseBlockTime
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultBlockTime01), resp)
+	err = json.Unmarshal([]byte(fixtures.BlockTime01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.BlockTimeResponse01 = resp
 	s.BlockTimeResult01 = 1574721591
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultBlockTime02), resp)
+	err = json.Unmarshal([]byte(fixtures.BlockTime02), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.BlockTimeResponse02 = resp
 	s.BlockTimeResult02 = ErrTimeStampNotAvailable
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultBlockTime03), resp)
+	err = json.Unmarshal([]byte(fixtures.BlockTime03), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.BlockTimeResponse03 = resp
 	// TestParseClusterNodes
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultClusterNodes01), resp)
+	err = json.Unmarshal([]byte(fixtures.ClusterNodes01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ClusterNodesResponse01 = resp
 	s.ClusterNodesResult01 = ContactInfo{PubKey: "9QzsJf7LPLj8GkXbYT3LFDKqsj2hHG7TA3xinJHu8epQ",
@@ -143,59 +144,59 @@ func (s *RPCResultTestSuite) SetupTest() {
 	s.ClusterNodesResult02 = ContactInfo{PubKey: "6xnLs5AnhkTkNcgArVSopx32sheFim1oGQwBWUJJXG1F", Gossip: "3.14.216.138:11000"}
 	// TestParseConfirmedBlock
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlock01), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlock01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlockResponse01 = resp
 	s.ConfirmedBlockResult01Blockhash = "3Eq21vXNB5s86c62bVuUfTeaMif1N2kUqRPBmGRJhyTA"
 	s.ConfirmedBlockResult01BlockTime = 0
 	s.ConfirmedBlockResult01TxAcctKeyLen = 5
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlock02), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlock02), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlockResponse02 = resp
 	s.ConfirmBlockResult02 = ErrSpecifiedBlockNotConfirmed
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlock03), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlock03), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlockResponse03 = resp
-	s.ConfirmBlockResult03 = errors.New("Slot 76884393 was skipped, or missing due to ledger jump to recent snapshot")
+	s.ConfirmBlockResult03 = &RPCError{Code: ErrCodeSlotSkipped, Message: "Slot 76884393 was skipped, or missing due to ledger jump to recent snapshot"}
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlock04), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlock04), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlockResponse04 = resp
 	s.ConfirmedBlockResult04RewardType = "Fee"
 	s.ConfirmedBlockResult04TxLen = 0
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlock05), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlock05), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlockResponse05 = resp
 	s.ConfirmedBlockResult05SigLen = 7
 	// TestParseConfirmedBlocks
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlocks01), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlocks01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlocksResponse01 = resp
 	s.ConfirmedBlocksResult01LenOfResult = 5
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlocks02), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlocks02), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlocksResponse02 = resp
 	s.ConfirmedBlocksResult02LenOfResult = 0
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlocks03), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlocks03), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlocksResponse03 = resp
-	s.ConfirmedBlocksResult03 = errors.New("Slot range too large; max 500000")
+	s.ConfirmedBlocksResult03 = &RPCError{Code: ErrCodeInvalidParams, Message: "Slot range too large; max 500000"}
 	// TestParaseConfirmedBlocksLimit
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedBlockWithLimit01), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedBlockWithLimit01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedBlocksLimitResponse01 = resp
 	s.ConfirmedBlocksLimitResult01Len = 3
 	// TestParseConfirmedSignaturesForAddress2
 	// TODO: test Memo when the type is confirmed
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultConfirmedSignaturesForAddress201), resp)
+	err = json.Unmarshal([]byte(fixtures.ConfirmedSignaturesForAddress201), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.ConfirmedSignaturesForAddress2Response01 = resp
 	s.ConfirmedSignaturesForAddress201Result01BlockTime = 1620403540
@@ -203,27 +204,27 @@ func (s *RPCResultTestSuite) SetupTest() {
 	s.ConfirmedSignaturesForAddress201Result01Memo = ""
 	// TestParseTokenSupply
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultTokenSupply01), resp)
+	err = json.Unmarshal([]byte(fixtures.TokenSupply01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.TokenSupplyResponse01 = resp
 	s.TokenSupplyResult01Amount = "555000000000000"
 	s.TokenSupplyResult01Decimals = uint8(6)
 	// TestParseTokenAccountBalance
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultTokenAccountBalance01), resp)
+	err = json.Unmarshal([]byte(fixtures.TokenAccountBalance01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.TokenAccountBalanceResponse01 = resp
 	s.TokenAccountBalanceResult01 = "5065734"
 
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultTokenAccountBalance02), resp)
+	err = json.Unmarshal([]byte(fixtures.TokenAccountBalance02), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.TokenAccountBalanceResponse02 = resp
-	s.TokenAccountBalanceResult02 = errors.New("Invalid param: not a v2.0 Token account")
+	s.TokenAccountBalanceResult02 = &RPCError{Code: ErrCodeInvalidParams, Message: "Invalid param: not a v2.0 Token account"}
 	// TestParseTokenAccountsByDelegate
 
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultTokenAccountsByDelegate01), resp)
+	err = json.Unmarshal([]byte(fixtures.TokenAccountsByDelegate01), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.TokenAccountsByDelegateResponse01 = resp
 	s.TokenAccountsByDelegateResult01Owner = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
@@ -231,7 +232,7 @@ func (s *RPCResultTestSuite) SetupTest() {
 	s.TokenAccountsByDelegateResult01Amount = "1"
 
 	resp = new(RPCResponse)
-	err = json.Unmarshal([]byte(testResultTokenAccountsByDelegate02), resp)
+	err = json.Unmarshal([]byte(fixtures.TokenAccountsByDelegate02), resp)
 	assert.NoError(s.T(), err, "prepare mock data fail")
 	s.TokenAccountsByDelegateResponse02 = resp
 	s.TokenAccountsByDelegateResult02Lamports = uint64(1726080)
@@ -245,19 +246,42 @@ func TestRPCResultTestSuite(t *testing.T) {
 
 func (s *RPCResultTestSuite) TestParseAccountInfo() {
 	fmt.Println("--------TestParseAccountInfoResponse--------")
-	acct1Info, err := ParseAccountInfoResponse(s.AccountInfoValueResponse01)
+	acct1Info, _, err := ParseAccountInfoResponse(s.AccountInfoValueResponse01)
 	assert.NoError(s.T(), err)
 	assert.EqualValues(s.T(), s.AccountInfoValueResult01, *acct1Info)
-	_, err = ParseAccountInfoResponse(s.AccountInfoValueResponse02)
+	_, _, err = ParseAccountInfoResponse(s.AccountInfoValueResponse02)
 	assert.Equal(s.T(), s.AccountInfoValueResult02, err)
-	_, err = ParseAccountInfoResponse(s.AccountInfoValueResponse03)
+	_, _, err = ParseAccountInfoResponse(s.AccountInfoValueResponse03)
 	assert.Equal(s.T(), s.AccountInfoValueResult03, err, "Error Mismatch")
 }
 func (s *RPCResultTestSuite) TestParseBalanceResponse() {
 	fmt.Println("--------TestParseBalanceResponse--------")
-	balance, err := ParseBalanceResponse(s.BalanceResponse01)
+	balance, rpcCtx, err := ParseBalanceResponse(s.BalanceResponse01)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), s.BalanceResult01, balance, "balance mismatch")
+	assert.Equal(s.T(), RPCContext{Slot: 76954704}, rpcCtx, "context mismatch")
+}
+
+func (s *RPCResultTestSuite) TestParseContextResult() {
+	fmt.Println("--------TestParseContextResult--------")
+	resp := &RPCResponse{Result: []byte(`{"context":{"apiVersion":"1.14.17","slot":171546},"value":{"amount":"5065734","decimals":2}}`)}
+	value := new(TokenValue)
+	rpcCtx, err := ParseContextResult(resp, value)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), RPCContext{Slot: 171546, APIVersion: "1.14.17"}, rpcCtx)
+	assert.Equal(s.T(), "5065734", value.Amount)
+
+	resp = &RPCResponse{Result: []byte(`{"context":{"slot":171547},"value":null}`)}
+	rpcCtx, err = ParseContextResult(resp, new(TokenValue))
+	assert.Equal(s.T(), ErrNullResult, err)
+	assert.Equal(s.T(), uint64(171547), rpcCtx.Slot)
+	_, rpcCtx, err = ParseAccountInfoResponse(resp)
+	assert.Equal(s.T(), ErrAccountNotExist, err)
+	assert.Equal(s.T(), uint64(171547), rpcCtx.Slot)
+
+	// a null result is no value either
+	_, _, err = ParseTokenSupply(&RPCResponse{Result: json.RawMessage("null")})
+	assert.Equal(s.T(), ErrNullResult, err)
 }
 
 func (s *RPCResultTestSuite) TestParseBlockCommitment() {
@@ -265,7 +289,8 @@ func (s *RPCResultTestSuite) TestParseBlockCommitment() {
 	commitment, err := ParseBlockCommitmentResponse(s.BlockCommitmentResponse01)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), s.BlockCommitmentResult01, *commitment, "commitment Mismatch")
-
+	_, err = ParseBlockCommitmentResponse(&RPCResponse{Result: json.RawMessage("null")})
+	assert.Equal(s.T(), ErrNullResult, err)
 }
 func (s *RPCResultTestSuite) TestParseBlockTime() {
 	fmt.Println("--------TestParseBlockTime--------")
@@ -285,6 +310,8 @@ func (s *RPCResultTestSuite) TestParseClusterNodes() {
 	assert.Equal(s.T(), s.ClusterNodesResult01, nodes[0])
 	// testing null in the field
 	assert.Equal(s.T(), s.ClusterNodesResult02, nodes[1])
+	_, err = ParseClusterNodesResponse(&RPCResponse{Result: json.RawMessage("null")})
+	assert.Equal(s.T(), ErrSpecifiedBlockNotConfirmed, err)
 }
 func (s *RPCResultTestSuite) TestParseConfirmedBlock() {
 	fmt.Println("--------TestParseConfirmedBlock--------")
@@ -297,6 +324,10 @@ func (s *RPCResultTestSuite) TestParseConfirmedBlock() {
 	assert.Equal(s.T(), s.ConfirmBlockResult02, err)
 	_, err = ParseConfirmedBlockResponse(s.ConfirmedBlockResponse03)
 	assert.Equal(s.T(), s.ConfirmBlockResult03, err)
+	assert.True(s.T(), errors.Is(err, ErrSlotSkipped))
+	rpcErr := new(RPCError)
+	assert.True(s.T(), errors.As(err, &rpcErr))
+	assert.Equal(s.T(), ErrCodeSlotSkipped, rpcErr.Code)
 
 	info, err = ParseConfirmedBlockResponse(s.ConfirmedBlockResponse04)
 	assert.NoError(s.T(), err)
@@ -336,20 +367,24 @@ func (s *RPCResultTestSuite) TestParseConfirmedSignaturesForAddress2() {
 	assert.Equal(s.T(), s.ConfirmedSignaturesForAddress201Result01BlockTime, sig[0].BlockTime)
 	assert.Equal(s.T(), s.ConfirmedSignaturesForAddress201Result01Confirm, sig[0].ConfirmationStatus)
 	assert.Equal(s.T(), s.ConfirmedSignaturesForAddress201Result01Memo, sig[0].Memo)
+	// a null result is no signatures
+	sig, err = ParseConfirmedSignaturesForAddress2(&RPCResponse{Result: json.RawMessage("null")})
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), sig)
 }
 func (s *RPCResultTestSuite) TestParseTokenSupply() {
 	fmt.Println("--------TestParseTokenSupply--------")
-	supply, err := ParseTokenSupply(s.TokenSupplyResponse01)
+	supply, _, err := ParseTokenSupply(s.TokenSupplyResponse01)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), s.TokenSupplyResult01Amount, supply.Amount)
 	assert.Equal(s.T(), s.TokenSupplyResult01Decimals, supply.Decimals)
 }
 func (s *RPCResultTestSuite) TestParseTokenAccountBalance() {
 	fmt.Println("--------TestParseTokenAccountBalance--------")
-	supply, err := ParseTokenSupply(s.TokenAccountBalanceResponse01)
+	supply, _, err := ParseTokenSupply(s.TokenAccountBalanceResponse01)
 	assert.NoError(s.T(), err)
 	assert.Equal(s.T(), s.TokenAccountBalanceResult01, supply.Amount)
-	_, err = ParseTokenSupply(s.TokenAccountBalanceResponse02)
+	_, _, err = ParseTokenSupply(s.TokenAccountBalanceResponse02)
 	assert.Error(s.T(), err)
 	assert.Equal(s.T(), s.TokenAccountBalanceResult02, err)
 
@@ -358,9 +393,9 @@ func (s *RPCResultTestSuite) TestParseTokenAccountBalance() {
 // TODO: ask solana team for the data structure of return. It seems the example does not match documentation
 func (s *RPCResultTestSuite) TestParseTokenAccountsByDelegate() {
 	fmt.Println("--------TestParseTokenAccountsByDelegate--------")
-	_, err := ParseTokenAccountsByDelegate(s.TokenAccountsByDelegateResponse01)
+	_, _, err := ParseTokenAccountsByDelegate(s.TokenAccountsByDelegateResponse01)
 	assert.NoError(s.T(), err)
 
-	_, err = ParseTokenAccountsByDelegate(s.TokenAccountsByDelegateResponse02)
+	_, _, err = ParseTokenAccountsByDelegate(s.TokenAccountsByDelegateResponse02)
 	assert.NoError(s.T(), err)
 }
@@ -0,0 +1,209 @@
+package solanarpc
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultThrottlePause is how long requests are held after an HTTP 429 without Retry-After
+	DefaultThrottlePause = time.Second
+	// ThrottleRecovery is how long it takes after the last 429 to double the slowed rate again
+	ThrottleRecovery = 10 * time.Second
+	// maxThrottleLevel bounds the slow down to rate/2^maxThrottleLevel
+	maxThrottleLevel = 3
+)
+
+// RateLimiter is a client side token bucket limiter, with one bucket for all
+// requests and optional stricter buckets per method. Every host gets its own
+// set of buckets, so behind a FailoverEndpoint each host has its own quota.
+// After an HTTP 429 it pauses the host and halves its global rate, then
+// recovers over ThrottleRecovery.
+type RateLimiter struct {
+	mu      sync.Mutex
+	global  *bucketLimit
+	methods map[string]*bucketLimit
+	hosts   map[string]*hostLimit
+}
+
+type bucketLimit struct {
+	rate  float64
+	burst int
+}
+
+// hostLimit is the state a RateLimiter keeps for one host
+type hostLimit struct {
+	global      *tokenBucket
+	methods     map[string]*tokenBucket
+	pausedUntil time.Time
+}
+
+type tokenBucket struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	throttleLevel int
+	throttledAt   time.Time
+}
+
+// NewRateLimiter limits all requests to a host to ratePerSecond with bursts
+// of burst; ratePerSecond <= 0 leaves the global rate unlimited
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	l := &RateLimiter{methods: make(map[string]*bucketLimit), hosts: make(map[string]*hostLimit)}
+	if ratePerSecond > 0 {
+		l.global = &bucketLimit{rate: ratePerSecond, burst: burst}
+	}
+	return l
+}
+
+// SetMethodLimit adds a limit for one RPC method on top of the global limit
+func (l *RateLimiter) SetMethodLimit(method string, ratePerSecond float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, h := range l.hosts {
+		delete(h.methods, method)
+	}
+	if ratePerSecond <= 0 {
+		delete(l.methods, method)
+		return
+	}
+	l.methods[method] = &bucketLimit{rate: ratePerSecond, burst: burst}
+}
+
+// host returns the state of host, creating it on first use. Callers hold l.mu.
+func (l *RateLimiter) host(host string) *hostLimit {
+	h, ok := l.hosts[host]
+	if !ok {
+		h = &hostLimit{methods: make(map[string]*tokenBucket)}
+		if l.global != nil {
+			h.global = newTokenBucket(l.global.rate, l.global.burst)
+		}
+		l.hosts[host] = h
+	}
+	return h
+}
+
+// Wait blocks until a request for method may be sent to host. When ctx has a
+// deadline that comes before the request would be allowed, Wait fails right
+// away with ErrRateLimited instead of sleeping.
+func (l *RateLimiter) Wait(ctx context.Context, host, method string) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	h := l.host(host)
+	buckets := []*tokenBucket{}
+	if h.global != nil {
+		buckets = append(buckets, h.global)
+	}
+	if limit, ok := l.methods[method]; ok {
+		b, ok := h.methods[method]
+		if !ok {
+			b = newTokenBucket(limit.rate, limit.burst)
+			h.methods[method] = b
+		}
+		buckets = append(buckets, b)
+	}
+	delay := h.pausedUntil.Sub(now)
+	for _, b := range buckets {
+		if d := b.delay(now); d > delay {
+			delay = d
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok && delay > 0 && now.Add(delay).After(deadline) {
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// hand the reserved tokens back
+		l.mu.Lock()
+		for _, b := range buckets {
+			b.tokens = math.Min(b.tokens+1, b.burst)
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Throttle holds every request to host for retryAfter (DefaultThrottlePause
+// when 0) and slows the global rate of host down. RPCClient calls it on HTTP
+// 429; other hosts are not affected.
+func (l *RateLimiter) Throttle(host string, retryAfter time.Duration) {
+	if l == nil {
+		return
+	}
+	if retryAfter <= 0 {
+		retryAfter = DefaultThrottlePause
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	h := l.host(host)
+	if until := now.Add(retryAfter); until.After(h.pausedUntil) {
+		h.pausedUntil = until
+	}
+	if h.global != nil {
+		h.global.refill(now)
+		if h.global.level(now) < maxThrottleLevel {
+			h.global.throttleLevel = h.global.level(now) + 1
+		}
+		h.global.throttledAt = now
+	}
+}
+
+func newTokenBucket(ratePerSecond float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: ratePerSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// level is the current slow down step; it drops by one every ThrottleRecovery
+func (b *tokenBucket) level(now time.Time) int {
+	if b.throttleLevel == 0 {
+		return 0
+	}
+	level := b.throttleLevel - int(now.Sub(b.throttledAt)/ThrottleRecovery)
+	if level < 0 {
+		return 0
+	}
+	return level
+}
+
+func (b *tokenBucket) effectiveRate(now time.Time) float64 {
+	return b.rate / math.Pow(2, float64(b.level(now)))
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.effectiveRate(now))
+		b.last = now
+	}
+}
+
+// delay returns how long until one token is available
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.effectiveRate(now) * float64(time.Second))
+}
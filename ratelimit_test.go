@@ -0,0 +1,82 @@
+package solanarpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := NewRateLimiter(0, 0)
+	limiter.SetMethodLimit("getConfirmedBlock", 20, 1)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(ctx, "node", "getConfirmedBlock"))
+	}
+	assert.True(t, time.Since(start) >= 90*time.Millisecond, "method limit should space out calls")
+
+	start = time.Now()
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, limiter.Wait(ctx, "node", "getBalance"))
+	}
+	assert.True(t, time.Since(start) < 10*time.Millisecond, "unlimited methods should not wait")
+}
+
+func TestRateLimiterFailFast(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	assert.NoError(t, limiter.Wait(context.Background(), "node", "getBalance"))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Equal(t, ErrRateLimited, limiter.Wait(ctx, "node", "getBalance"))
+	assert.True(t, time.Since(start) < 50*time.Millisecond, "should fail without sleeping")
+}
+
+func TestRateLimiterThrottleOn429(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := RPCClient{RateLimit: NewRateLimiter(100, 10)}
+	assert.NoError(t, client.Init(srv.URL))
+	_, err := client.GetBalance(context.Background(), "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Equal(t, ErrRateLimited, err, "requests should be held after a 429")
+	assert.Equal(t, 50.0, client.RateLimit.hosts[srv.URL].global.effectiveRate(time.Now()))
+}
+
+func TestRateLimiterThrottlePerHost(t *testing.T) {
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	ok := httptest.NewServer(http.HandlerFunc(serveBalance))
+	defer ok.Close()
+
+	endpoint, err := NewFailoverEndpoint(limited.URL, ok.URL)
+	assert.NoError(t, err)
+	client := RPCClient{Endpoint: endpoint, RateLimit: NewRateLimiter(100, 10)}
+	_, err = client.GetBalance(context.Background(), "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	resp, err := client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.NoError(t, err, "a 429 from one host should not hold requests to another")
+	assert.Equal(t, ok.URL, resp.Host)
+	_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Equal(t, ErrRateLimited, err, "the throttled host should still be held")
+}
@@ -0,0 +1,180 @@
+package solanarpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// NonIdempotentMethods are never resent by a RetryPolicy unless
+// RetryNonIdempotent is set, since a lost reply does not mean the call failed
+var NonIdempotentMethods = map[string]bool{
+	"sendTransaction": true,
+	"requestAirdrop":  true,
+}
+
+// retryableRPCCodes are server error codes reporting a temporary node state
+var retryableRPCCodes = map[int]bool{
+	ErrCodeNodeUnhealthy:              true,
+	ErrCodeBlockStatusNotAvailableYet: true,
+	ErrCodeMinContextSlotNotReached:   true,
+}
+
+// RetryPolicy configures how RPCClient resends failed requests. A request is
+// attempted until it succeeds, the error is not retryable, MaxAttempts is
+// reached or the next wait would exceed MaxElapsed.
+type RetryPolicy struct {
+	MaxAttempts    int           // total attempts including the first; 0 means no limit
+	MaxElapsed     time.Duration // total time budget; 0 means no limit
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64 // randomizes each wait by +/- this fraction, 0 to 1
+	// RetryNonIdempotent allows resending the methods in NonIdempotentMethods
+	RetryNonIdempotent bool
+	// Classifier decides whether err is worth another attempt; nil means IsRetryable
+	Classifier func(err error) bool
+}
+
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		MaxElapsed:     30 * time.Second,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// IsRetryable reports whether err is temporary: network timeouts, refused or
+// reset connections, connections closed early, client side timeouts, HTTP
+// 429/502/503/504 and node errors such as -32005 (node behind). Other transport
+// failures, such as a bad scheme, TLS or DNS errors, are permanent.
+// Cancellation or expiry of the caller's context is never retryable.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrRequestTimeout) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return retryableRPCCodes[rpcErr.Code]
+	}
+	// every *url.Error is a net.Error, so only its timeouts say the failure is temporary
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// allows reports whether requests for all methods may be resent
+func (p *RetryPolicy) allows(methods ...string) bool {
+	if p == nil {
+		return false
+	}
+	if p.RetryNonIdempotent {
+		return true
+	}
+	for _, method := range methods {
+		if NonIdempotentMethods[method] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Classifier != nil {
+		return p.Classifier(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff returns the wait before attempt n+1, n starting at 1
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(n-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(wait)
+}
+
+// do runs attempt until it succeeds or the policy gives up, and returns the
+// last error
+func (p *RetryPolicy) do(ctx context.Context, logger Logger, method string, attempt func() error) error {
+	start := time.Now()
+	for n := 1; ; n++ {
+		err := attempt()
+		if err == nil || !p.retryable(err) {
+			return err
+		}
+		if p.MaxAttempts > 0 && n >= p.MaxAttempts {
+			return err
+		}
+		if p.MaxAttempts <= 0 && p.MaxElapsed <= 0 {
+			return err
+		}
+		wait := p.backoff(n)
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && httpErr.RetryAfter > wait {
+			wait = httpErr.RetryAfter
+		}
+		if p.MaxElapsed > 0 && time.Since(start)+wait > p.MaxElapsed {
+			return err
+		}
+		logger.WithFields(Fields{"func": "retry", "method": method, "attempt": n, "wait": wait}).Debug(err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if len(value) == 0 {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
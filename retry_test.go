@@ -0,0 +1,129 @@
+package solanarpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"solanarpc/internal/fixtures"
+)
+
+// flakyHandler fails the first failures calls with fail, then answers with a balance
+func flakyHandler(calls *int, failures int, fail func(w http.ResponseWriter, req *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		*calls++
+		if *calls <= failures {
+			fail(w, req)
+			return
+		}
+		serveBalance(w, req)
+	}
+}
+
+func TestRetryOnHTTPStatus(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(flakyHandler(&calls, 2, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := RPCClient{Retry: fastRetryPolicy()}
+	assert.NoError(t, client.Init(srv.URL))
+	resp, err := client.GetBalance(context.Background(), "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.JSONEq(t, balanceResult, string(resp.Result), "the answer of the last attempt is returned")
+}
+
+func TestRetryOnNodeBehind(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(flakyHandler(&calls, 5, func(w http.ResponseWriter, req *http.Request) {
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		w.Header().Set("Content-Type", HTTPContentType)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Node is behind by 42 slots"},"id":%d}`, rpcReq.ID)
+	}))
+	defer srv.Close()
+
+	client := RPCClient{Retry: fastRetryPolicy()}
+	assert.NoError(t, client.Init(srv.URL))
+	resp, err := client.GetBalance(context.Background(), "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.NoError(t, err, "node errors are returned in the response once retries run out")
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, -32005, resp.Error.Code)
+}
+
+func TestRetrySkipsNonIdempotent(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(flakyHandler(&calls, 1, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := RPCClient{Retry: fastRetryPolicy()}
+	assert.NoError(t, client.Init(srv.URL))
+	_, err := client.DoPostRequest(context.Background(), NewRPCRequest("sendTransaction", "tx"))
+	httpErr := new(HTTPError)
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	assert.Equal(t, 1, calls)
+
+	client.Retry.RetryNonIdempotent = true
+	calls = 0
+	_, err = client.DoPostRequest(context.Background(), NewRPCRequest("sendTransaction", "tx"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(&HTTPError{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, IsRetryable(&HTTPError{StatusCode: http.StatusBadRequest}))
+	assert.False(t, IsRetryable(context.DeadlineExceeded))
+	assert.False(t, IsRetryable(ErrInvalidFuncParameter))
+	assert.True(t, IsRetryable(&url.Error{Op: "Post", URL: "http://node", Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}))
+	assert.True(t, IsRetryable(&url.Error{Op: "Post", URL: "http://node", Err: io.EOF}))
+	assert.True(t, IsRetryable(&url.Error{Op: "Post", URL: "http://node", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}))
+	assert.False(t, IsRetryable(&url.Error{Op: "Post", URL: "http://node", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}))
+	assert.False(t, IsRetryable(&url.Error{Op: "Post", URL: "ftp://node", Err: errors.New("unsupported protocol scheme \"ftp\"")}))
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+}
+
+// A transport that fails for good must neither be retried nor failed over
+func TestPermanentTransportErrors(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", HTTPContentType)
+		w.Write([]byte(fixtures.Balance01))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	// the test server certificate is not trusted by a default client
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL}, WithRetryPolicy(fastRetryPolicy()))
+	assert.NoError(t, err)
+	_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Error(t, err)
+	assert.False(t, IsRetryable(err), "TLS error %v", err)
+
+	attempts := 0
+	transport := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("signing key unavailable")
+	})
+	client, err = NewClient(SolanaEndpoint{Host: srv.URL}, WithHTTPClient(&http.Client{Transport: transport}), WithRetryPolicy(fastRetryPolicy()))
+	assert.NoError(t, err)
+	_, err = client.GetBalance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
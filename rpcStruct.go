@@ -19,18 +19,41 @@ type RPCResponse struct {
 	ID      uint64           `json:"id"`
 	Error   RPCResponseError `json:"error,omitempty"`
 	Result  json.RawMessage  `json:"result"`
+	// Host is the endpoint address that served the request
+	Host string `json:"-"`
 }
 
 type RPCResponseError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Err returns the error as an *RPCError, or nil when the response has none
+func (e RPCResponseError) Err() error {
+	if e.Code == 0 {
+		return nil
+	}
+	return &RPCError{Code: e.Code, Message: e.Message, Data: e.Data}
 }
 
 type RPCResult struct {
-	Context struct {
-		Slot uint64 `json:"slot,omitempty"`
-	} `json:"context,omitempty"`
-	Value json.RawMessage `json:"value,omitempty"`
+	Context RPCContext      `json:"context,omitempty"`
+	Value   json.RawMessage `json:"value,omitempty"`
+}
+
+// ContextResult is a {context, value} result whose value is decoded into
+// what Value points to. A null value leaves Value nil.
+type ContextResult struct {
+	Context RPCContext  `json:"context"`
+	Value   interface{} `json:"value"`
+}
+
+// RPCContext is the context object of {context, value} results and notifications.
+// APIVersion is only sent by newer nodes.
+type RPCContext struct {
+	Slot       uint64 `json:"slot,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
 }
 
 // This struct is for sending commitment as an object
@@ -132,6 +155,8 @@ type Transaction struct {
 			NumReadonlySignedAccounts   uint64 `json:"numReadonlySignedAccounts"`
 			NumReadonlyUnsignedAccounts uint64 `json:"numReadonlyUnsignedAccounts"`
 		} `json:"header"`
+		RecentBlockhash string                `json:"recentBlockhash,omitempty"`
+		Instructions    []CompiledInstruction `json:"instructions,omitempty"`
 	} `json:"message"`
 }
 
@@ -149,11 +174,87 @@ type CompiledInstruction struct {
 type TokenBalance struct {
 	AccountIndex  uint64 `json:"accountIndex"`
 	Mint          string `json:"mint"`
+	Owner         string `json:"owner,omitempty"`
 	UITokenAmount struct {
-		Amount         string `json:"amount"`
-		Decimals       uint64 `json:"decimals"`
-		UiAmountString string `json:"uiAmountString"`
-	} `josn:"uiTokenAmount"`
+		Amount         string   `json:"amount"`
+		Decimals       uint64   `json:"decimals"`
+		UiAmount       *float64 `json:"uiAmount"` // null for a zero amount
+		UiAmountString string   `json:"uiAmountString"`
+	} `json:"uiTokenAmount"`
+}
+
+// GetTransaction
+
+type TransactionParamObj struct {
+	Encoding   EncodeMethod  `json:"encoding,omitempty"`
+	Commitment CommitmentVal `json:"commitment,omitempty"`
+}
+
+type ConfirmedTransaction struct {
+	Slot        uint64             `json:"slot"`
+	BlockTime   *int64             `json:"blockTime"` // null when the node has no time for the block
+	Transaction EncodedTransaction `json:"transaction"`
+	Meta        *TransactionMeta   `json:"meta"` // null when the node has no status for it
+}
+
+type TransactionMeta struct {
+	Err               *TransactionError          `json:"err"` // null when the transaction succeeded
+	Fee               uint64                     `json:"fee"`
+	PreBalances       []uint64                   `json:"preBalances"`
+	PostBalances      []uint64                   `json:"postBalances"`
+	PreTokenBalances  []TokenBalance             `json:"preTokenBalances,omitempty"`
+	PostTokenBalances []TokenBalance             `json:"postTokenBalances,omitempty"`
+	InnerInstructions []CompiledInnerInstruction `json:"innerInstructions,omitempty"`
+	LogMessages       []string                   `json:"logMessages,omitempty"`
+	Rewards           []Reward                   `json:"rewards,omitempty"`
+	// ParsedInnerInstructions replaces InnerInstructions with the jsonParsed encoding
+	ParsedInnerInstructions []ParsedInnerInstruction `json:"-"`
+}
+
+// ParsedTransaction is a transaction in the jsonParsed encoding
+type ParsedTransaction struct {
+	Signatures []string `json:"signatures"`
+	Message    struct {
+		AccountKeys     []ParsedAccountKey  `json:"accountKeys"`
+		RecentBlockhash string              `json:"recentBlockhash,omitempty"`
+		Instructions    []ParsedInstruction `json:"instructions,omitempty"`
+	} `json:"message"`
+}
+
+type ParsedAccountKey struct {
+	Pubkey   string `json:"pubkey"`
+	Signer   bool   `json:"signer"`
+	Writable bool   `json:"writable"`
+}
+
+// ParsedInstruction is an instruction in the jsonParsed encoding. The node
+// fills Program and Parsed for the programs it knows, such as "system" or
+// "spl-token", and Accounts and Data for the others.
+type ParsedInstruction struct {
+	ProgramId string          `json:"programId"`
+	Program   string          `json:"program,omitempty"`
+	Parsed    json.RawMessage `json:"parsed,omitempty"` // e.g. {"type":"transfer","info":{...}}
+	Accounts  []string        `json:"accounts,omitempty"`
+	Data      string          `json:"data,omitempty"`
+}
+
+type ParsedInnerInstruction struct {
+	Index        uint64              `json:"index"`
+	Instructions []ParsedInstruction `json:"instructions"`
+}
+
+// GetSignatureStatuses
+
+type SignatureStatusesParamExtra struct {
+	SearchTransactionHistory bool `json:"searchTransactionHistory,omitempty"`
+}
+
+// SignatureStatus is nil in the results for a signature the node does not know
+type SignatureStatus struct {
+	Slot               uint64            `json:"slot"`
+	Confirmations      *uint64           `json:"confirmations"` // null once rooted
+	ConfirmationStatus CommitmentVal     `json:"confirmationStatus,omitempty"`
+	Err                *TransactionError `json:"err"` // null when the transaction succeeded
 }
 
 // GetBlockProduction
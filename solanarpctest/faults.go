@@ -0,0 +1,195 @@
+package solanarpctest
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"solanarpc"
+)
+
+type FaultKind int
+
+const (
+	// FaultLatency delays the answer by Latency; other rules still apply
+	FaultLatency FaultKind = iota + 1
+	// FaultDropConnection closes the connection without an answer
+	FaultDropConnection
+	// FaultHTTPStatus answers with StatusCode, e.g. 429 or 502
+	FaultHTTPStatus
+	// FaultMalformedJSON answers with a truncated JSON body
+	FaultMalformedJSON
+	// FaultWrongID answers normally under other IDs, to trigger ErrIDMismatch
+	FaultWrongID
+	// FaultRPCError answers every request with RPCError
+	FaultRPCError
+)
+
+// Fault is a rule making a Server misbehave. Method, After, Times and
+// Probability select the requests it applies to; the first matching rule
+// other than FaultLatency decides the answer.
+type Fault struct {
+	Kind FaultKind
+	// Method limits the rule to requests, or batches, holding this method;
+	// empty matches every request
+	Method string
+	// After lets the first After matching requests through
+	After int
+	// Times is how many requests the rule applies to; 0 means no limit
+	Times int
+	// Probability applies the rule to a random share of the matching
+	// requests; 0 means all of them
+	Probability float64
+
+	Latency    time.Duration
+	StatusCode int
+	// RetryAfter is sent as the Retry-After header of a FaultHTTPStatus
+	RetryAfter time.Duration
+	RPCError   *solanarpc.RPCError
+}
+
+func LatencyFault(latency time.Duration) Fault {
+	return Fault{Kind: FaultLatency, Latency: latency}
+}
+
+func DropConnectionFault() Fault {
+	return Fault{Kind: FaultDropConnection}
+}
+
+func HTTPStatusFault(statusCode int) Fault {
+	return Fault{Kind: FaultHTTPStatus, StatusCode: statusCode}
+}
+
+func MalformedJSONFault() Fault {
+	return Fault{Kind: FaultMalformedJSON}
+}
+
+func WrongIDFault() Fault {
+	return Fault{Kind: FaultWrongID}
+}
+
+func RPCErrorFault(code int, message string) Fault {
+	return Fault{Kind: FaultRPCError, RPCError: &solanarpc.RPCError{Code: code, Message: message}}
+}
+
+type faultRule struct {
+	Fault
+	matched int
+	applied int
+}
+
+// AddFault adds a rule after the existing ones
+func (m *Server) AddFault(fault Fault) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = append(m.rules, &faultRule{Fault: fault})
+}
+
+// ClearFaults removes every rule, the server behaves again
+func (m *Server) ClearFaults() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = nil
+}
+
+// SetFaultSeed seeds the draws of Fault.Probability, so a failing run can be
+// repeated. A new Server uses seed 1.
+func (m *Server) SetFaultSeed(seed int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rnd = rand.New(rand.NewSource(seed))
+}
+
+// faults applies the latency rules matching a request for methods and
+// returns the first other matching rule, if any
+func (m *Server) faults(ctx context.Context, methods []string) *Fault {
+	var latency time.Duration
+	var decided *Fault
+	m.mu.Lock()
+	for _, rule := range m.rules {
+		if !rule.matches(methods) || (decided != nil && rule.Kind != FaultLatency) {
+			continue
+		}
+		rule.matched++
+		if rule.matched <= rule.After || (rule.Times > 0 && rule.applied >= rule.Times) {
+			continue
+		}
+		if rule.Probability > 0 && m.rnd.Float64() >= rule.Probability {
+			continue
+		}
+		rule.applied++
+		if rule.Kind == FaultLatency {
+			latency += rule.Latency
+			continue
+		}
+		fault := rule.Fault
+		decided = &fault
+	}
+	m.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+	return decided
+}
+
+func (f *Fault) matches(methods []string) bool {
+	if len(f.Method) == 0 {
+		return true
+	}
+	for _, method := range methods {
+		if method == f.Method {
+			return true
+		}
+	}
+	return false
+}
+
+// serveFault answers with fault when it replaces the whole answer, and
+// reports whether it did
+func (m *Server) serveFault(w http.ResponseWriter, fault Fault) bool {
+	switch fault.Kind {
+	case FaultDropConnection:
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				conn.Close()
+				return true
+			}
+		}
+		panic(http.ErrAbortHandler)
+	case FaultHTTPStatus:
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(fault.RetryAfter/time.Second)))
+		}
+		w.WriteHeader(fault.StatusCode)
+		w.Write([]byte(http.StatusText(fault.StatusCode)))
+		return true
+	case FaultMalformedJSON:
+		m.write(w, []byte(`{"jsonrpc":"2.0","result":{"context":{"slot":`))
+		return true
+	}
+	return false
+}
+
+// shiftID moves the ID of an encoded reply by one
+func shiftID(reply json.RawMessage) json.RawMessage {
+	msg := map[string]json.RawMessage{}
+	if err := json.Unmarshal(reply, &msg); err != nil {
+		return reply
+	}
+	id, err := strconv.ParseUint(string(msg["id"]), 10, 64)
+	if err != nil {
+		id = 0
+	}
+	msg["id"] = json.RawMessage(strconv.FormatUint(id+1, 10))
+	shifted, _ := json.Marshal(msg)
+	return shifted
+}
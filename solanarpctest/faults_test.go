@@ -0,0 +1,143 @@
+package solanarpctest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"solanarpc"
+)
+
+func fastRetryPolicy() *solanarpc.RetryPolicy {
+	return &solanarpc.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Multiplier: 2}
+}
+
+type FaultSuite struct {
+	suite.Suite
+	server *Server
+	ctx    context.Context
+}
+
+func (s *FaultSuite) SetupTest() {
+	s.server = NewServer()
+	s.ctx = context.Background()
+}
+
+func (s *FaultSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *FaultSuite) balance(client *solanarpc.RPCClient) error {
+	resp, err := client.GetBalance(s.ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	if err != nil {
+		return err
+	}
+	_, _, err = solanarpc.ParseBalanceResponse(resp)
+	return err
+}
+
+func (s *FaultSuite) TestRetriesRecover() {
+	fault := HTTPStatusFault(http.StatusBadGateway)
+	fault.Times = 2
+	s.server.AddFault(fault)
+	fault = RPCErrorFault(solanarpc.ErrCodeNodeUnhealthy, "Node is unhealthy")
+	fault.Method = "getBalance"
+	fault.Times = 1
+	s.server.AddFault(fault)
+	fault = DropConnectionFault()
+	fault.Times = 1
+	s.server.AddFault(fault)
+
+	client := s.server.Client(solanarpc.WithRetryPolicy(fastRetryPolicy()))
+	client.Retry.MaxAttempts = 5
+	s.NoError(s.balance(client))
+	s.Len(s.server.Requests(), 5)
+
+	// without retries each fault reaches the caller
+	s.server.Reset()
+	s.server.ClearFaults()
+	for _, fault := range []Fault{HTTPStatusFault(http.StatusTooManyRequests), RPCErrorFault(solanarpc.ErrCodeNodeUnhealthy, "Node is unhealthy"), DropConnectionFault()} {
+		fault.Times = 1
+		s.server.AddFault(fault)
+	}
+	client = s.server.Client()
+	var httpErr *solanarpc.HTTPError
+	s.True(errors.As(s.balance(client), &httpErr))
+	s.Equal(http.StatusTooManyRequests, httpErr.StatusCode)
+	s.True(errors.Is(s.balance(client), solanarpc.ErrNodeUnhealthy))
+	s.True(solanarpc.IsRetryable(s.balance(client)))
+	s.NoError(s.balance(client))
+}
+
+func (s *FaultSuite) TestBrokenAnswers() {
+	client := s.server.Client()
+	s.server.AddFault(WrongIDFault())
+	s.Equal(solanarpc.ErrIDMismatch, s.balance(client))
+	_, err := client.DoBatchRequest(s.ctx, []solanarpc.RPCRequest{solanarpc.NewRPCRequest("getBalance", "a"), solanarpc.NewRPCRequest("getBalance", "b")})
+	s.Equal(solanarpc.ErrIDMismatch, err)
+
+	s.server.ClearFaults()
+	s.server.AddFault(MalformedJSONFault())
+	s.Error(s.balance(client))
+}
+
+func (s *FaultSuite) TestSelection() {
+	fault := HTTPStatusFault(http.StatusServiceUnavailable)
+	fault.Method = "getBlockTime"
+	fault.After = 1
+	fault.Times = 2
+	s.server.AddFault(fault)
+	client := s.server.Client()
+
+	s.NoError(s.balance(client))
+	blockTimeErr := func() error {
+		_, err := client.GetBlockTime(s.ctx, 5)
+		return err
+	}
+	s.NoError(blockTimeErr())
+	s.Error(blockTimeErr())
+	s.Error(blockTimeErr())
+	s.NoError(blockTimeErr())
+
+	s.server.ClearFaults()
+	s.server.SetFaultSeed(7)
+	fault = DropConnectionFault()
+	fault.Probability = 0.5
+	s.server.AddFault(fault)
+	failures := 0
+	for i := 0; i < 100; i++ {
+		if s.balance(client) != nil {
+			failures++
+		}
+	}
+	s.InDelta(50, failures, 20)
+}
+
+func (s *FaultSuite) TestLatency() {
+	s.server.AddFault(LatencyFault(200 * time.Millisecond))
+	client := s.server.Client(solanarpc.WithTimeout(50 * time.Millisecond))
+	s.Equal(solanarpc.ErrRequestTimeout, s.balance(client))
+}
+
+func (s *FaultSuite) TestFailover() {
+	backup := NewServer()
+	defer backup.Close()
+	s.server.AddFault(DropConnectionFault())
+	endpoint, err := solanarpc.NewFailoverEndpoint(s.server.URL, backup.URL)
+	s.NoError(err)
+	endpoint.MaxFailures = 1
+	client, err := solanarpc.NewClient(endpoint, solanarpc.WithRetryPolicy(fastRetryPolicy()))
+	s.NoError(err)
+	for i := 0; i < 3; i++ {
+		s.NoError(s.balance(client))
+	}
+	s.False(endpoint.Hosts()[0].Healthy)
+	s.Len(backup.Requests(), 3)
+}
+
+func TestFaults(t *testing.T) {
+	suite.Run(t, new(FaultSuite))
+}
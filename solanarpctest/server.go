@@ -0,0 +1,283 @@
+// Package solanarpctest provides an in-process Solana JSON-RPC node, with
+// recorded answers and fault injection, to test code built on solanarpc.
+package solanarpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"solanarpc"
+	"solanarpc/internal/fixtures"
+)
+
+// Handler answers one request to a Server. A *solanarpc.RPCError result is
+// sent as the error member, any other error as an internal error.
+type Handler func(rpcReq solanarpc.RPCRequest) (result interface{}, err error)
+
+// Fixtures are the answers of a new Server, taken from real nodes
+var Fixtures = map[string]string{
+	"getAccountInfo":                    fixtures.AccountExsitBase58,
+	"getBalance":                        fixtures.Balance01,
+	"getBlockCommitment":                fixtures.BlockCommitment01,
+	"getBlockTime":                      fixtures.BlockTime01,
+	"getClusterNodes":                   fixtures.ClusterNodes01,
+	"getConfirmedBlock":                 fixtures.ConfirmedBlock01,
+	"getConfirmedBlocks":                fixtures.ConfirmedBlocks01,
+	"getConfirmedBlocksWithLimit":       fixtures.ConfirmedBlockWithLimit01,
+	"getConfirmedSignaturesForAddress2": fixtures.ConfirmedSignaturesForAddress201,
+	"getTokenAccountBalance":            fixtures.TokenAccountBalance01,
+	"getTokenAccountsByDelegate":        fixtures.TokenAccountsByDelegate01,
+	"getTokenSupply":                    fixtures.TokenSupply01,
+	"getTransaction":                    fixtures.Transaction01,
+}
+
+// Server is an in-process Solana JSON-RPC node for tests. It answers
+// single and batch requests from a registry of handlers by method, echoing
+// the caller's ID, serves /health and keeps every request it receives.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	raw      map[string]json.RawMessage
+	requests []solanarpc.RPCRequest
+	health   string
+	rules    []*faultRule
+	rnd      *rand.Rand
+}
+
+type mockReply struct {
+	Version string                      `json:"jsonrpc"`
+	ID      json.RawMessage             `json:"id"`
+	Result  json.RawMessage             `json:"result,omitempty"`
+	Error   *solanarpc.RPCResponseError `json:"error,omitempty"`
+}
+
+// NewServer starts a Server answering Fixtures. Close it when done.
+func NewServer() *Server {
+	m := &Server{handlers: make(map[string]Handler), raw: make(map[string]json.RawMessage), health: "ok", rnd: rand.New(rand.NewSource(1))}
+	for method, fixture := range Fixtures {
+		m.HandleFixture(method, fixture)
+	}
+	m.Server = httptest.NewServer(m)
+	return m
+}
+
+// Client returns a client of the server built by solanarpc.NewClient with opts
+func (m *Server) Client(opts ...solanarpc.Option) *solanarpc.RPCClient {
+	client, _ := solanarpc.NewClient(solanarpc.SolanaEndpoint{Host: m.URL}, opts...)
+	return client
+}
+
+// Handle answers method with handler, replacing any previous answer
+func (m *Server) Handle(method string, handler Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[method] = handler
+	delete(m.raw, method)
+}
+
+// HandleResult answers method with result whatever the params
+func (m *Server) HandleResult(method string, result interface{}) {
+	m.Handle(method, func(solanarpc.RPCRequest) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// HandleFixture answers method with the result or error of a complete
+// response such as the ones in Fixtures
+func (m *Server) HandleFixture(method, fixture string) error {
+	resp := solanarpc.RPCResponse{}
+	if err := json.Unmarshal([]byte(fixture), &resp); err != nil {
+		return err
+	}
+	m.Handle(method, func(solanarpc.RPCRequest) (interface{}, error) {
+		if err := resp.Error.Err(); err != nil {
+			return nil, err
+		}
+		return resp.Result, nil
+	})
+	return nil
+}
+
+// HandleRaw answers method with response as is, ID included
+func (m *Server) HandleRaw(method, response string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.raw[method] = json.RawMessage(response)
+	delete(m.handlers, method)
+}
+
+// SetHealth sets the body served on /health, "ok" by default
+func (m *Server) SetHealth(health string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.health = health
+}
+
+// Requests returns the requests received so far, batch items included
+func (m *Server) Requests() []solanarpc.RPCRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]solanarpc.RPCRequest(nil), m.requests...)
+}
+
+// RequestsFor returns the requests received for method
+func (m *Server) RequestsFor(method string) []solanarpc.RPCRequest {
+	reqs := []solanarpc.RPCRequest{}
+	for _, rpcReq := range m.Requests() {
+		if rpcReq.Method == method {
+			reqs = append(reqs, rpcReq)
+		}
+	}
+	return reqs
+}
+
+// Reset forgets the requests received so far
+func (m *Server) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = nil
+}
+
+func (m *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet && req.URL.Path == "/health" {
+		m.mu.Lock()
+		health := m.health
+		m.mu.Unlock()
+		w.Write([]byte(health))
+		return
+	}
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	batch := len(trimmed) > 0 && trimmed[0] == '['
+	msgs := []json.RawMessage{trimmed}
+	if batch {
+		if err := json.Unmarshal(trimmed, &msgs); err != nil || len(msgs) == 0 {
+			m.write(w, m.invalid(solanarpc.ErrCodeInvalidRequest, "Invalid request"))
+			return
+		}
+	}
+	calls := make([]mockCall, len(msgs))
+	methods := make([]string, len(msgs))
+	for i, msg := range msgs {
+		calls[i] = m.decode(msg)
+		methods[i] = calls[i].rpcReq.Method
+	}
+
+	fault := m.faults(req.Context(), methods)
+	if fault != nil && m.serveFault(w, *fault) {
+		return
+	}
+	replies := make([]json.RawMessage, len(calls))
+	for i, call := range calls {
+		if fault != nil && fault.Kind == FaultRPCError && call.err == nil {
+			replies[i] = errorReply(call.id, fault.RPCError)
+			continue
+		}
+		replies[i] = m.answer(call)
+	}
+	if fault != nil && fault.Kind == FaultWrongID {
+		for i := range replies {
+			replies[i] = shiftID(replies[i])
+		}
+	}
+	if !batch {
+		m.write(w, replies[0])
+		return
+	}
+	out, _ := json.Marshal(replies)
+	m.write(w, out)
+}
+
+func (m *Server) write(w http.ResponseWriter, out []byte) {
+	w.Header().Set("Content-Type", solanarpc.HTTPContentType)
+	w.Write(out)
+}
+
+// mockCall is one decoded request; err is the reply to a request that
+// could not be decoded
+type mockCall struct {
+	rpcReq solanarpc.RPCRequest
+	id     json.RawMessage
+	err    json.RawMessage
+}
+
+// decode parses and records one encoded request
+func (m *Server) decode(msg json.RawMessage) mockCall {
+	envelope := struct {
+		ID json.RawMessage `json:"id"`
+	}{}
+	call := mockCall{}
+	if err := json.Unmarshal(msg, &call.rpcReq); err != nil {
+		call.err = m.invalid(solanarpc.ErrCodeParseError, "Parse error")
+		return call
+	}
+	json.Unmarshal(msg, &envelope)
+	call.id = envelope.ID
+	m.mu.Lock()
+	m.requests = append(m.requests, call.rpcReq)
+	m.mu.Unlock()
+	return call
+}
+
+// answer runs the handler of one request
+func (m *Server) answer(call mockCall) json.RawMessage {
+	if call.err != nil {
+		return call.err
+	}
+	m.mu.Lock()
+	handler, ok := m.handlers[call.rpcReq.Method]
+	raw, isRaw := m.raw[call.rpcReq.Method]
+	m.mu.Unlock()
+	if isRaw {
+		return raw
+	}
+	if !ok {
+		return errorReply(call.id, &solanarpc.RPCError{Code: solanarpc.ErrCodeMethodNotFound, Message: "Method not found"})
+	}
+	result, err := handler(call.rpcReq)
+	if err != nil {
+		var rpcErr *solanarpc.RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &solanarpc.RPCError{Code: solanarpc.ErrCodeInternalError, Message: err.Error()}
+		}
+		return errorReply(call.id, rpcErr)
+	}
+	reply := mockReply{Version: solanarpc.RequestVersion, ID: call.id}
+	if reply.Result, err = json.Marshal(result); err != nil {
+		return errorReply(call.id, &solanarpc.RPCError{Code: solanarpc.ErrCodeInternalError, Message: err.Error()})
+	}
+	return mustMarshalReply(reply)
+}
+
+func errorReply(id json.RawMessage, rpcErr *solanarpc.RPCError) json.RawMessage {
+	return mustMarshalReply(mockReply{Version: solanarpc.RequestVersion, ID: id, Error: &solanarpc.RPCResponseError{Code: rpcErr.Code, Message: rpcErr.Message, Data: rpcErr.Data}})
+}
+
+func (m *Server) invalid(code int, message string) []byte {
+	return mustMarshalReply(mockReply{Version: solanarpc.RequestVersion, ID: json.RawMessage("null"), Error: &solanarpc.RPCResponseError{Code: code, Message: message}})
+}
+
+func mustMarshalReply(reply mockReply) []byte {
+	out, err := json.Marshal(reply)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
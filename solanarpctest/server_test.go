@@ -0,0 +1,106 @@
+package solanarpctest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"solanarpc"
+	"solanarpc/internal/fixtures"
+)
+
+type ServerSuite struct {
+	suite.Suite
+	server *Server
+	client *solanarpc.RPCClient
+	ctx    context.Context
+}
+
+func (s *ServerSuite) SetupTest() {
+	s.server = NewServer()
+	s.client = s.server.Client()
+	s.ctx = context.Background()
+}
+
+func (s *ServerSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ServerSuite) TestFixtures() {
+	resp, err := s.client.GetBalance(s.ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	s.NoError(err)
+	balance, _, err := solanarpc.ParseBalanceResponse(resp)
+	s.NoError(err)
+	s.Equal(uint64(27020041285), balance)
+
+	resp, err = s.client.GetAccountInfo(s.ctx, "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", &solanarpc.AccountInfoExtraParams{Encoding: string(solanarpc.Base58)})
+	s.NoError(err)
+	_, _, err = solanarpc.ParseAccountInfoResponse(resp)
+	s.NoError(err)
+
+	resp, err = s.client.GetTokenSupply(s.ctx, "3wyAj7Rt1TWVPZVteFJPLa26JmLvdb1CAKEFZm3NY75E", nil)
+	s.NoError(err)
+	_, _, err = solanarpc.ParseTokenSupply(resp)
+	s.NoError(err)
+
+	reqs := s.server.RequestsFor("getAccountInfo")
+	s.Len(reqs, 1)
+	s.Equal("CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", reqs[0].Params[0])
+	s.Equal("base58", reqs[0].Params[1].(map[string]interface{})["encoding"])
+	s.Len(s.server.Requests(), 3)
+	s.server.Reset()
+	s.Empty(s.server.Requests())
+}
+
+func (s *ServerSuite) TestHandlers() {
+	s.server.Handle("getBlockTime", func(rpcReq solanarpc.RPCRequest) (interface{}, error) {
+		if rpcReq.Params[0] == float64(5) {
+			return nil, &solanarpc.RPCError{Code: solanarpc.ErrCodeBlockNotAvailable, Message: "Block not available for slot 5"}
+		}
+		return 1574721591, nil
+	})
+	resp, err := s.client.GetBlockTime(s.ctx, 6)
+	s.NoError(err)
+	blockTime, err := solanarpc.ParseBlockTimeResponse(resp)
+	s.NoError(err)
+	s.Equal(uint64(1574721591), blockTime)
+
+	resp, err = s.client.GetBlockTime(s.ctx, 5)
+	s.NoError(err)
+	_, err = solanarpc.ParseBlockTimeResponse(resp)
+	s.True(errors.Is(err, solanarpc.ErrBlockNotAvailable))
+
+	resp, err = s.client.DoPostRequest(s.ctx, solanarpc.NewRPCRequest("getNoSuchThing"))
+	s.NoError(err)
+	s.True(errors.Is(resp.Error.Err(), solanarpc.ErrMethodNotFound))
+}
+
+func (s *ServerSuite) TestIDMismatch() {
+	// a raw fixture keeps its recorded id, which is not the caller's
+	s.server.HandleRaw("getBalance", fixtures.Balance01)
+	_, err := s.client.GetBalance(s.ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	s.Equal(solanarpc.ErrIDMismatch, err)
+}
+
+func (s *ServerSuite) TestBatch() {
+	s.server.HandleResult("getSlot", 42)
+	reqs := []solanarpc.RPCRequest{solanarpc.NewRPCRequest("getSlot"), solanarpc.NewRPCRequest("getBalance", "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")}
+	resps, err := s.client.DoBatchRequest(s.ctx, reqs)
+	s.NoError(err)
+	s.Equal(reqs[0].ID, resps[0].ID)
+	s.Equal("42", string(resps[0].Result))
+	balance, _, err := solanarpc.ParseBalanceResponse(resps[1])
+	s.NoError(err)
+	s.Equal(uint64(27020041285), balance)
+}
+
+func (s *ServerSuite) TestHealth() {
+	s.True(s.client.CheckHealth(s.ctx))
+	s.server.SetHealth("behind")
+	s.False(s.client.CheckHealth(s.ctx))
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(ServerSuite))
+}
@@ -0,0 +1,101 @@
+package solanarpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// TraceParentHeader is the W3C Trace Context header carrying the caller span
+const TraceParentHeader = "traceparent"
+
+// Span attribute keys, following the OpenTelemetry RPC conventions
+const (
+	AttrRPCSystem      = "rpc.system"
+	AttrRPCMethod      = "rpc.method"
+	AttrRPCRequestID   = "rpc.jsonrpc.request_id"
+	AttrRPCErrorCode   = "rpc.jsonrpc.error_code"
+	AttrEndpoint       = "server.address"
+	AttrHTTPStatusCode = "http.status_code"
+	AttrBatchSize      = "rpc.batch_size"
+	AttrSubscriptionID = "rpc.subscription_id"
+)
+
+// spanPrefix starts the name of every span, followed by the method
+const spanPrefix = "solanarpc "
+
+// Tracer opens spans around RPC calls. It is small on purpose so it can be
+// adapted to OpenTelemetry or any other tracing library.
+type Tracer interface {
+	// Start opens a span named name as a child of the span in ctx, if any, and
+	// returns a ctx carrying the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttribute(key string, value interface{})
+	// RecordError marks the span failed
+	RecordError(err error)
+	// TraceParent returns the W3C traceparent value of the span, empty when
+	// the span is not sampled or not propagated
+	TraceParent() string
+	End()
+}
+
+// NopTracer opens spans that record nothing
+type NopTracer struct{}
+
+func (NopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(key string, value interface{}) {}
+func (nopSpan) RecordError(err error)                      {}
+func (nopSpan) TraceParent() string                        { return "" }
+func (nopSpan) End()                                       {}
+
+type spanKey struct{}
+
+// startSpan opens a span with tracer and makes the HTTP requests sent with
+// the returned ctx carry its traceparent
+func startSpan(ctx context.Context, tracer Tracer, name string) (context.Context, Span) {
+	ctx, span := tracer.Start(ctx, name)
+	span.SetAttribute(AttrRPCSystem, "jsonrpc")
+	if traceParent := span.TraceParent(); len(traceParent) > 0 {
+		ctx = WithHeader(ctx, http.Header{http.CanonicalHeaderKey(TraceParentHeader): {traceParent}})
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// spanFromContext returns the span of the request in flight, so the HTTP
+// layer can add what only it knows
+func spanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span
+	}
+	return nopSpan{}
+}
+
+// endSpan records how a call went and ends span
+func endSpan(span Span, rpcErr RPCResponseError, err error) {
+	if err == nil && rpcErr.Code != 0 {
+		err = rpcErr.Err()
+	}
+	var codeErr *RPCError
+	if errors.As(err, &codeErr) {
+		span.SetAttribute(AttrRPCErrorCode, codeErr.Code)
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+func (r *RPCClient) tracer() Tracer {
+	if r.Tracer == nil {
+		return NopTracer{}
+	}
+	return r.Tracer
+}
@@ -0,0 +1,137 @@
+package solanarpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordTracer keeps every span it opens
+type recordTracer struct {
+	mu    sync.Mutex
+	spans []*recordSpan
+}
+
+type recordSpan struct {
+	mu    sync.Mutex
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (t *recordTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordSpan{name: name, attrs: map[string]interface{}{}}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return ctx, span
+}
+
+func (t *recordTracer) find(name string) *recordSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, span := range t.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func (s *recordSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs[key] = value
+}
+
+func (s *recordSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *recordSpan) TraceParent() string {
+	return "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+}
+
+func (s *recordSpan) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ended = true
+}
+
+func TestTracerSpans(t *testing.T) {
+	traceParents := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		traceParents <- req.Header.Get(TraceParentHeader)
+		w.Header().Set("Content-Type", HTTPContentType)
+		rpcReq := RPCRequest{}
+		decodeRequest(req, &rpcReq)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","error":{"code":-32004,"message":"Block not available"},"id":%d}`, rpcReq.ID)
+	}))
+	defer srv.Close()
+
+	tracer := &recordTracer{}
+	client, err := NewClient(SolanaEndpoint{Host: srv.URL}, WithTracer(tracer))
+	assert.NoError(t, err)
+	rpcReq := NewRPCRequest("getBlockTime", 5)
+	_, err = client.DoPostRequest(context.Background(), rpcReq)
+	assert.NoError(t, err)
+	assert.Equal(t, (&recordSpan{}).TraceParent(), <-traceParents)
+
+	span := tracer.find("solanarpc getBlockTime")
+	assert.NotNil(t, span)
+	assert.True(t, span.ended)
+	assert.Equal(t, "getBlockTime", span.attrs[AttrRPCMethod])
+	assert.Equal(t, rpcReq.ID, span.attrs[AttrRPCRequestID])
+	assert.Equal(t, srv.URL, span.attrs[AttrEndpoint])
+	assert.Equal(t, http.StatusOK, span.attrs[AttrHTTPStatusCode])
+	assert.Equal(t, ErrCodeBlockNotAvailable, span.attrs[AttrRPCErrorCode])
+	assert.ErrorIs(t, span.err, ErrBlockNotAvailable)
+
+	client.DoBatchRequest(context.Background(), []RPCRequest{NewRPCRequest("getSlot"), NewRPCRequest("getSlot")})
+	<-traceParents
+	span = tracer.find("solanarpc batch")
+	assert.NotNil(t, span)
+	assert.True(t, span.ended)
+	assert.Equal(t, 2, span.attrs[AttrBatchSize])
+	assert.Error(t, span.err)
+}
+
+func TestPubSubTracerSpans(t *testing.T) {
+	srv := pubSubStandIn(t)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := DialPubSub(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"))
+	assert.NoError(t, err)
+	defer client.Close()
+	tracer := &recordTracer{}
+	client.SetTracer(tracer)
+
+	slot, err := client.SlotSubscribe(ctx)
+	assert.NoError(t, err)
+	<-slot.C
+	span := tracer.find("solanarpc slotSubscribe")
+	assert.NotNil(t, span)
+	assert.True(t, span.ended)
+	assert.NoError(t, span.err)
+	assert.Eventually(t, func() bool {
+		span := tracer.find("solanarpc slotNotification")
+		if span == nil {
+			return false
+		}
+		span.mu.Lock()
+		defer span.mu.Unlock()
+		return span.ended && span.attrs[AttrSubscriptionID] == slot.ID()
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,195 @@
+package solanarpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// EncodedTransaction is the transaction of a ConfirmedTransaction. With the
+// json encoding it is decoded into Transaction, with jsonParsed into Parsed,
+// whose account keys are objects, and with a binary encoding Data and
+// Encoding hold it. Raw keeps it as sent.
+type EncodedTransaction struct {
+	Transaction *Transaction
+	Parsed      *ParsedTransaction
+	Data        string
+	Encoding    EncodeMethod
+	Raw         json.RawMessage
+}
+
+func (t *EncodedTransaction) UnmarshalJSON(raw []byte) error {
+	*t = EncodedTransaction{Raw: append(json.RawMessage(nil), raw...)}
+	trimmed := bytes.TrimSpace(raw)
+	if isNullJSON(trimmed) {
+		return nil
+	}
+	switch trimmed[0] {
+	case '[':
+		pair := []string{}
+		if err := json.Unmarshal(trimmed, &pair); err != nil {
+			return err
+		}
+		if len(pair) > 0 {
+			t.Data = pair[0]
+		}
+		if len(pair) > 1 {
+			t.Encoding = EncodeMethod(pair[1])
+		}
+	case '"':
+		// the deprecated binary encoding sends base58 data alone
+		t.Encoding = Base58
+		return json.Unmarshal(trimmed, &t.Data)
+	default:
+		if isParsedForm(trimmed) {
+			t.Parsed = new(ParsedTransaction)
+			return json.Unmarshal(trimmed, t.Parsed)
+		}
+		t.Transaction = new(Transaction)
+		return json.Unmarshal(trimmed, t.Transaction)
+	}
+	return nil
+}
+
+// isParsedForm reports whether a transaction object is in the jsonParsed
+// encoding, which sends account keys as objects instead of strings
+func isParsedForm(raw []byte) bool {
+	probe := struct {
+		Message struct {
+			AccountKeys []json.RawMessage `json:"accountKeys"`
+		} `json:"message"`
+	}{}
+	if json.Unmarshal(raw, &probe) != nil || len(probe.Message.AccountKeys) == 0 {
+		return false
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(probe.Message.AccountKeys[0]), []byte("{"))
+}
+
+func (t EncodedTransaction) MarshalJSON() ([]byte, error) {
+	switch {
+	case len(t.Raw) > 0:
+		return t.Raw, nil
+	case t.Transaction != nil:
+		return json.Marshal(t.Transaction)
+	case t.Parsed != nil:
+		return json.Marshal(t.Parsed)
+	case len(t.Data) > 0:
+		return json.Marshal([]string{t.Data, string(t.Encoding)})
+	}
+	return []byte("null"), nil
+}
+
+type transactionMeta TransactionMeta
+
+// UnmarshalJSON takes the inner instructions of the jsonParsed encoding,
+// which name programs and accounts by key, into ParsedInnerInstructions
+func (m *TransactionMeta) UnmarshalJSON(raw []byte) error {
+	meta := struct {
+		*transactionMeta
+		InnerInstructions json.RawMessage `json:"innerInstructions"`
+	}{transactionMeta: (*transactionMeta)(m)}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return err
+	}
+	m.InnerInstructions, m.ParsedInnerInstructions = nil, nil
+	if len(meta.InnerInstructions) == 0 || isNullJSON(meta.InnerInstructions) {
+		return nil
+	}
+	if hasParsedInstructions(meta.InnerInstructions) {
+		return json.Unmarshal(meta.InnerInstructions, &m.ParsedInnerInstructions)
+	}
+	return json.Unmarshal(meta.InnerInstructions, &m.InnerInstructions)
+}
+
+// hasParsedInstructions reports whether inner instructions name their program
+// by key, as the jsonParsed encoding does, instead of by account index
+func hasParsedInstructions(raw []byte) bool {
+	probe := []struct {
+		Instructions []map[string]json.RawMessage `json:"instructions"`
+	}{}
+	if json.Unmarshal(raw, &probe) != nil {
+		return false
+	}
+	for _, inner := range probe {
+		for _, instruction := range inner.Instructions {
+			if _, ok := instruction["programId"]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m TransactionMeta) MarshalJSON() ([]byte, error) {
+	if m.ParsedInnerInstructions == nil {
+		return json.Marshal(transactionMeta(m))
+	}
+	return json.Marshal(struct {
+		transactionMeta
+		InnerInstructions []ParsedInnerInstruction `json:"innerInstructions"`
+	}{transactionMeta(m), m.ParsedInnerInstructions})
+}
+
+// TransactionError is the err of a failed transaction, such as
+// "InsufficientFundsForFee" or {"InstructionError":[0,{"Custom":1}]}. Kind is
+// the name of the error and Detail what comes with it, if anything.
+type TransactionError struct {
+	Kind   string
+	Detail json.RawMessage
+}
+
+func (e *TransactionError) Error() string {
+	if len(e.Detail) == 0 {
+		return e.Kind
+	}
+	return e.Kind + ": " + string(e.Detail)
+}
+
+func (e *TransactionError) UnmarshalJSON(raw []byte) error {
+	e.Detail = nil
+	if json.Unmarshal(raw, &e.Kind) == nil {
+		return nil
+	}
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return err
+	}
+	for kind, detail := range object {
+		e.Kind, e.Detail = kind, detail
+	}
+	return nil
+}
+
+func (e TransactionError) MarshalJSON() ([]byte, error) {
+	if len(e.Detail) == 0 {
+		return json.Marshal(e.Kind)
+	}
+	return json.Marshal(map[string]json.RawMessage{e.Kind: e.Detail})
+}
+
+// commitmentLevel orders commitments, deprecated names included, by lower case name
+var commitmentLevel = map[string]int{
+	string(Processed): 1, "recent": 1, "single": 1,
+	string(Confirmed): 2, "singlegossip": 2,
+	string(Finalized): 3, "max": 3, "root": 3,
+}
+
+func levelOf(commitment CommitmentVal) int {
+	return commitmentLevel[strings.ToLower(string(commitment))]
+}
+
+// Reached reports whether the transaction reached commitment. Nodes older
+// than confirmationStatus only tell a rooted transaction, which is finalized.
+func (s *SignatureStatus) Reached(commitment CommitmentVal) bool {
+	if s == nil || levelOf(commitment) == 0 {
+		return false
+	}
+	status := s.ConfirmationStatus
+	if len(status) == 0 {
+		status = Processed
+		if s.Confirmations == nil {
+			status = Finalized
+		}
+	}
+	return levelOf(status) >= levelOf(commitment)
+}
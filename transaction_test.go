@@ -0,0 +1,204 @@
+package solanarpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"solanarpc"
+	"solanarpc/internal/fixtures"
+	"solanarpc/solanarpctest"
+)
+
+const testSignature = "2nBhEBYYvfaAe16UMNqRHre4YNSskvuYgx3M6E4JP1oDYvZEJHvoPzyUidNgNX5r9sTyN1J9UxtbCXy2rqYcuyuv"
+
+func TestGetTransaction(t *testing.T) {
+	server := solanarpctest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	resp, err := client.GetTransaction(ctx, testSignature, &solanarpc.TransactionParamObj{Encoding: solanarpc.Json, Commitment: "confirmed"})
+	assert.NoError(t, err)
+	tx, err := solanarpc.ParseTransactionResponse(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(430), tx.Slot)
+	assert.Equal(t, int64(1624821990), *tx.BlockTime)
+	assert.Equal(t, []string{testSignature}, tx.Transaction.Transaction.Signatures)
+	assert.Equal(t, "mfcyqEXB3DnHXki6KjjmZck6YjmZLvpAByy2fj4nh6B", tx.Transaction.Transaction.Message.RecentBlockhash)
+	assert.Equal(t, []uint64{0, 1}, tx.Transaction.Transaction.Message.Instructions[0].Accounts)
+	assert.Nil(t, tx.Meta.Err)
+	assert.Equal(t, uint64(5000), tx.Meta.Fee)
+	assert.Equal(t, []uint64{499998937500, 26853640, 1}, tx.Meta.PreBalances)
+	assert.Equal(t, []uint64{499998932500, 26858640, 1}, tx.Meta.PostBalances)
+	assert.Len(t, tx.Meta.LogMessages, 2)
+
+	reqs := server.RequestsFor("getTransaction")
+	assert.Len(t, reqs, 1)
+	assert.Equal(t, map[string]interface{}{"encoding": "json", "commitment": "confirmed"}, reqs[0].Params[1])
+
+	// jsonParsed names accounts and programs by key
+	server.HandleFixture("getTransaction", fixtures.Transaction03)
+	resp, err = client.GetTransaction(ctx, testSignature, &solanarpc.TransactionParamObj{Encoding: solanarpc.JsonParsed})
+	assert.NoError(t, err)
+	tx, err = solanarpc.ParseTransactionResponse(resp)
+	assert.NoError(t, err)
+	assert.Nil(t, tx.Transaction.Transaction)
+	parsed := tx.Transaction.Parsed
+	assert.Equal(t, []string{testSignature}, parsed.Signatures)
+	assert.Equal(t, solanarpc.ParsedAccountKey{Pubkey: "3UVYmECPPMZSCqWKfENfuoTv51fTDTWicX9xmBD2euKe", Signer: true, Writable: true}, parsed.Message.AccountKeys[0])
+	assert.Equal(t, "Vote111111111111111111111111111111111111111", parsed.Message.Instructions[0].ProgramId)
+	assert.Len(t, parsed.Message.Instructions[0].Accounts, 3)
+	assert.Empty(t, tx.Meta.InnerInstructions)
+	inner := tx.Meta.ParsedInnerInstructions[0].Instructions[0]
+	assert.Equal(t, "system", inner.Program)
+	assert.JSONEq(t, `{"type":"transfer","info":{"destination":"AjozzgE83A3x1sHNUR64hfH7zaEBWeMaFuAN9kQgujrc","lamports":5000,"source":"3UVYmECPPMZSCqWKfENfuoTv51fTDTWicX9xmBD2euKe"}}`, string(inner.Parsed))
+	out, err := json.Marshal(tx.Meta)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `"innerInstructions":[{"index":0,"instructions":[{"programId":"11111111111111111111111111111111","program":"system"`)
+
+	// a failed transaction in a binary encoding
+	server.HandleFixture("getTransaction", fixtures.Transaction02)
+	resp, err = client.GetTransaction(ctx, testSignature, &solanarpc.TransactionParamObj{Encoding: solanarpc.Base64})
+	assert.NoError(t, err)
+	tx, err = solanarpc.ParseTransactionResponse(resp)
+	assert.NoError(t, err)
+	assert.Nil(t, tx.Transaction.Transaction)
+	assert.Equal(t, solanarpc.Base64, tx.Transaction.Encoding)
+	assert.NotEmpty(t, tx.Transaction.Data)
+	assert.Nil(t, tx.BlockTime)
+	assert.Equal(t, "InstructionError", tx.Meta.Err.Kind)
+	assert.Equal(t, `InstructionError: [1,{"Custom":1}]`, tx.Meta.Err.Error())
+	assert.Equal(t, "5065734", tx.Meta.PreTokenBalances[0].UITokenAmount.Amount)
+	assert.Equal(t, 50657.34, *tx.Meta.PreTokenBalances[0].UITokenAmount.UiAmount)
+	assert.Equal(t, "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", tx.Meta.PostTokenBalances[0].Owner)
+	assert.Equal(t, uint64(4), tx.Meta.InnerInstructions[0].Instructions[0].ProgramIdIndex)
+
+	// a missing transaction
+	server.HandleResult("getTransaction", nil)
+	resp, err = client.GetTransaction(ctx, testSignature, nil)
+	assert.NoError(t, err)
+	_, err = solanarpc.ParseTransactionResponse(resp)
+	assert.Equal(t, solanarpc.ErrTransactionNotFound, err)
+	_, err = solanarpc.NewTypedClient(client).Transaction(ctx, testSignature, nil)
+	assert.True(t, errors.Is(err, solanarpc.ErrTransactionNotFound))
+	assert.True(t, errors.Is(err, solanarpc.ErrNullResult))
+
+	_, err = client.GetTransaction(ctx, "", nil)
+	assert.Equal(t, solanarpc.ErrInvalidFuncParameter, err)
+}
+
+func TestGetTransactionFallback(t *testing.T) {
+	server := solanarpctest.NewServer()
+	defer server.Close()
+	server.Handle("getTransaction", func(solanarpc.RPCRequest) (interface{}, error) {
+		return nil, &solanarpc.RPCError{Code: solanarpc.ErrCodeMethodNotFound, Message: "Method not found"}
+	})
+	server.HandleFixture("getConfirmedTransaction", fixtures.Transaction01)
+
+	tx, err := solanarpc.NewTypedClient(server.Client()).Transaction(context.Background(), testSignature, &solanarpc.TransactionParamObj{Encoding: solanarpc.Json})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(430), tx.Slot)
+	assert.Len(t, server.RequestsFor("getTransaction"), 1)
+	fallback := server.RequestsFor("getConfirmedTransaction")
+	assert.Len(t, fallback, 1)
+	assert.Equal(t, testSignature, fallback[0].Params[0])
+	assert.Equal(t, map[string]interface{}{"encoding": "json"}, fallback[0].Params[1])
+}
+
+func TestTransactionErrorJSON(t *testing.T) {
+	for _, raw := range []string{`"AccountInUse"`, `{"InstructionError":[0,{"Custom":1}]}`, `{"InsufficientFundsForRent":{"account_index":2}}`} {
+		txErr := new(solanarpc.TransactionError)
+		assert.NoError(t, json.Unmarshal([]byte(raw), txErr))
+		out, err := json.Marshal(txErr)
+		assert.NoError(t, err)
+		assert.JSONEq(t, raw, string(out))
+	}
+
+	encoded := solanarpc.EncodedTransaction{}
+	assert.NoError(t, json.Unmarshal([]byte(`"3Bxs4Bc3VYuGVB19"`), &encoded))
+	assert.Equal(t, "3Bxs4Bc3VYuGVB19", encoded.Data)
+	assert.Equal(t, solanarpc.Base58, encoded.Encoding)
+	assert.Error(t, json.Unmarshal([]byte(`{"signatures":"2nBh","message":{}}`), &encoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"signatures":[],"message":{"accountKeys":[{"pubkey":1}]}}`), &encoded))
+}
+
+func TestGetSignatureStatuses(t *testing.T) {
+	server := solanarpctest.NewServer()
+	defer server.Close()
+	slot := uint64(82)
+	server.Handle("getSignatureStatuses", func(rpcReq solanarpc.RPCRequest) (interface{}, error) {
+		slot++
+		statuses := []interface{}{}
+		for _, sig := range rpcReq.Params[0].([]interface{}) {
+			switch sig.(string) {
+			case "finalized":
+				statuses = append(statuses, map[string]interface{}{"slot": 72, "confirmations": nil, "err": nil, "confirmationStatus": "finalized"})
+			case "failed":
+				statuses = append(statuses, map[string]interface{}{"slot": 48, "confirmations": 10, "err": map[string]interface{}{"InstructionError": []interface{}{0, map[string]interface{}{"Custom": 1}}}, "confirmationStatus": "confirmed"})
+			case "legacy":
+				statuses = append(statuses, map[string]interface{}{"slot": 41, "confirmations": nil, "err": nil})
+			default:
+				statuses = append(statuses, nil)
+			}
+		}
+		return map[string]interface{}{"context": map[string]interface{}{"slot": slot}, "value": statuses}, nil
+	})
+	client := solanarpc.NewTypedClient(server.Client())
+	ctx := context.Background()
+
+	signatures := make([]string, 2*solanarpc.MaxSignatureStatuses+88)
+	for i := range signatures {
+		signatures[i] = "unknown"
+	}
+	signatures[0], signatures[solanarpc.MaxSignatureStatuses], signatures[len(signatures)-1] = "finalized", "failed", "legacy"
+	statuses, rpcCtx, err := client.SignatureStatuses(ctx, signatures, &solanarpc.SignatureStatusesParamExtra{SearchTransactionHistory: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(83), rpcCtx.Slot)
+	assert.Len(t, statuses, len(signatures))
+
+	reqs := server.RequestsFor("getSignatureStatuses")
+	assert.Len(t, reqs, 3)
+	assert.Len(t, reqs[0].Params[0], solanarpc.MaxSignatureStatuses)
+	assert.Len(t, reqs[2].Params[0], 88)
+	assert.Equal(t, map[string]interface{}{"searchTransactionHistory": true}, reqs[0].Params[1])
+
+	assert.Nil(t, statuses[1])
+	assert.False(t, statuses[1].Reached(solanarpc.Processed))
+	assert.Equal(t, uint64(72), statuses[0].Slot)
+	assert.Nil(t, statuses[0].Confirmations)
+	assert.True(t, statuses[0].Reached(solanarpc.Finalized))
+	assert.True(t, statuses[0].Reached(solanarpc.CommitmentDefault))
+
+	failed := statuses[solanarpc.MaxSignatureStatuses]
+	assert.Equal(t, solanarpc.Confirmed, failed.ConfirmationStatus)
+	assert.Equal(t, uint64(10), *failed.Confirmations)
+	assert.Equal(t, "InstructionError", failed.Err.Kind)
+	assert.True(t, failed.Reached(solanarpc.Confirmed))
+	assert.False(t, failed.Reached(solanarpc.Finalized))
+	assert.True(t, failed.Reached("singleGossip"))
+	assert.False(t, failed.Reached("max"))
+	assert.True(t, statuses[len(statuses)-1].Reached(solanarpc.Finalized))
+
+	server.Reset()
+	resps, err := client.GetSignatureStatuses(ctx, []string{"finalized"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, server.Requests()[0].Params, 1)
+	statuses, _, err = solanarpc.ParseSignatureStatuses([]string{"finalized"}, resps...)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 1)
+
+	// a node answering fewer or more statuses than asked is caught
+	_, _, err = solanarpc.ParseSignatureStatuses([]string{"finalized", "failed"}, resps...)
+	assert.Equal(t, solanarpc.ErrStatusCountMismatch, err)
+	server.Handle("getSignatureStatuses", func(solanarpc.RPCRequest) (interface{}, error) {
+		return map[string]interface{}{"context": map[string]interface{}{"slot": 90}, "value": []interface{}{nil}}, nil
+	})
+	_, _, err = client.SignatureStatuses(ctx, signatures, nil)
+	assert.Equal(t, solanarpc.ErrStatusCountMismatch, err)
+
+	_, err = client.GetSignatureStatuses(ctx, nil, nil)
+	assert.Equal(t, solanarpc.ErrInvalidFuncParameter, err)
+}
@@ -0,0 +1,168 @@
+package solanarpc
+
+import "context"
+
+// TypedClient returns parsed values instead of raw responses. Each method
+// sends the request of the matching Get* method and parses the answer with
+// the matching Parse* function. Methods whose result comes with a context
+// also return it. The raw API stays available through the embedded RPCClient.
+//
+// A null result the parser rejects, such as an account that does not exist,
+// is an error matching ErrNullResult as well as the sentinel of the method.
+type TypedClient struct {
+	*RPCClient
+}
+
+func NewTypedClient(client *RPCClient) *TypedClient {
+	return &TypedClient{RPCClient: client}
+}
+
+type nullResultError struct {
+	err error
+}
+
+func (e nullResultError) Error() string {
+	return e.err.Error()
+}
+
+func (e nullResultError) Is(target error) bool {
+	return target == ErrNullResult || target == e.err
+}
+
+// typedNullError turns the null error a parser returns, ErrNullResult or the
+// sentinel of the method, into one matching both; other errors pass as they are
+func typedNullError(err, nullErr error) error {
+	if nullErr == nil {
+		nullErr = ErrNullResult
+	}
+	if err == ErrNullResult || err == nullErr {
+		return nullResultError{err: nullErr}
+	}
+	return err
+}
+
+func (t *TypedClient) AccountInfo(ctx context.Context, publicKey string, extra *AccountInfoExtraParams) (*AccountInfoValue, RPCContext, error) {
+	resp, err := t.GetAccountInfo(ctx, publicKey, extra)
+	if err != nil {
+		return nil, RPCContext{}, err
+	}
+	value, rpcCtx, err := ParseAccountInfoResponse(resp)
+	return value, rpcCtx, typedNullError(err, ErrAccountNotExist)
+}
+
+func (t *TypedClient) Balance(ctx context.Context, publicKey string) (uint64, RPCContext, error) {
+	resp, err := t.GetBalance(ctx, publicKey)
+	if err != nil {
+		return 0, RPCContext{}, err
+	}
+	balance, rpcCtx, err := ParseBalanceResponse(resp)
+	return balance, rpcCtx, typedNullError(err, nil)
+}
+
+func (t *TypedClient) BlockCommitment(ctx context.Context, block uint64) (*BlockCommitment, error) {
+	resp, err := t.GetBlockCommitment(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ParseBlockCommitmentResponse(resp)
+	return value, typedNullError(err, ErrUnknownBlock)
+}
+
+func (t *TypedClient) BlockTime(ctx context.Context, block uint64) (uint64, error) {
+	resp, err := t.GetBlockTime(ctx, block)
+	if err != nil {
+		return 0, err
+	}
+	value, err := ParseBlockTimeResponse(resp)
+	return value, typedNullError(err, ErrTimeStampNotAvailable)
+}
+
+func (t *TypedClient) ClusterNodes(ctx context.Context) ([]ContactInfo, error) {
+	resp, err := t.GetClusterNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ParseClusterNodesResponse(resp)
+	return value, typedNullError(err, ErrSpecifiedBlockNotConfirmed)
+}
+
+func (t *TypedClient) ConfirmedBlock(ctx context.Context, params *ConfirmedBlockParam) (*ConfirmedBlock, error) {
+	resp, err := t.GetConfirmedBlock(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ParseConfirmedBlockResponse(resp)
+	return value, typedNullError(err, ErrSpecifiedBlockNotConfirmed)
+}
+
+func (t *TypedClient) ConfirmedBlocks(ctx context.Context, params *ConfirmedBlocksParam) ([]uint64, error) {
+	resp, err := t.GetConfirmedBlocks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ParseConfirmedBlocks(resp)
+	return value, typedNullError(err, nil)
+}
+
+func (t *TypedClient) ConfirmedBlocksWithLimit(ctx context.Context, params *ConfirmedBlocksWithLimitParam) ([]uint64, error) {
+	resp, err := t.GetConfirmedBlocksWithLimit(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ParseConfimedBlocksLimit(resp)
+	return value, typedNullError(err, nil)
+}
+
+func (t *TypedClient) ConfirmedSignaturesForAddress2(ctx context.Context, base58Sig string, extra *ConfirmedSignaturesForAddress2ParamExtra) ([]ConfirmedSignaturesForAddress2, error) {
+	resp, err := t.GetConfirmedSignaturesForAddress2(ctx, base58Sig, extra)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ParseConfirmedSignaturesForAddress2(resp)
+	return value, typedNullError(err, nil)
+}
+
+func (t *TypedClient) TokenSupply(ctx context.Context, base58Pubkey string, commitment *CommitmentConfig) (*TokenValue, RPCContext, error) {
+	resp, err := t.GetTokenSupply(ctx, base58Pubkey, commitment)
+	if err != nil {
+		return nil, RPCContext{}, err
+	}
+	value, rpcCtx, err := ParseTokenSupply(resp)
+	return value, rpcCtx, typedNullError(err, ErrAccountNotExist)
+}
+
+func (t *TypedClient) TokenAccountBalance(ctx context.Context, base58Pubkey string, commitment *CommitmentConfig) (*TokenValue, RPCContext, error) {
+	resp, err := t.GetTokenAccountBalance(ctx, base58Pubkey, commitment)
+	if err != nil {
+		return nil, RPCContext{}, err
+	}
+	value, rpcCtx, err := ParseTokenAccountBalance(resp)
+	return value, rpcCtx, typedNullError(err, ErrAccountNotExist)
+}
+
+func (t *TypedClient) TokenAccountsByDelegate(ctx context.Context, base58Pubkey string, addrOrID interface{}, extra *TokenAccountsByDelegateParamExtra) ([]TokenAccountsByDelegateValue, RPCContext, error) {
+	resp, err := t.GetTokenAccountsByDelegate(ctx, base58Pubkey, addrOrID, extra)
+	if err != nil {
+		return nil, RPCContext{}, err
+	}
+	value, rpcCtx, err := ParseTokenAccountsByDelegate(resp)
+	return value, rpcCtx, typedNullError(err, nil)
+}
+
+func (t *TypedClient) Transaction(ctx context.Context, signature string, config *TransactionParamObj) (*ConfirmedTransaction, error) {
+	resp, err := t.GetTransaction(ctx, signature, config)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ParseTransactionResponse(resp)
+	return value, typedNullError(err, ErrTransactionNotFound)
+}
+
+func (t *TypedClient) SignatureStatuses(ctx context.Context, signatures []string, extra *SignatureStatusesParamExtra) ([]*SignatureStatus, RPCContext, error) {
+	resps, err := t.GetSignatureStatuses(ctx, signatures, extra)
+	if err != nil {
+		return nil, RPCContext{}, err
+	}
+	statuses, rpcCtx, err := ParseSignatureStatuses(signatures, resps...)
+	return statuses, rpcCtx, typedNullError(err, nil)
+}
@@ -0,0 +1,75 @@
+package solanarpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"solanarpc"
+	"solanarpc/internal/fixtures"
+	"solanarpc/solanarpctest"
+)
+
+func TestTypedClient(t *testing.T) {
+	server := solanarpctest.NewServer()
+	defer server.Close()
+	client := solanarpc.NewTypedClient(server.Client())
+	ctx := context.Background()
+
+	balance, rpcCtx, err := client.Balance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(27020041285), balance)
+	assert.Equal(t, solanarpc.RPCContext{Slot: 76954704}, rpcCtx)
+
+	account, rpcCtx, err := client.AccountInfo(ctx, "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, account)
+	assert.NotZero(t, rpcCtx.Slot)
+
+	blockTime, err := client.BlockTime(ctx, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1574721591), blockTime)
+
+	// null results, at the top or as the value, fail the same way
+	server.HandleFixture("getAccountInfo", fixtures.AccountNotExist)
+	account, rpcCtx, err = client.AccountInfo(ctx, "CM78CPUeXjn8o3yroDHxUtKsZZgoy4GPkPPXfouKNH12", nil)
+	assert.Nil(t, account)
+	assert.NotZero(t, rpcCtx.Slot)
+	assert.True(t, errors.Is(err, solanarpc.ErrNullResult))
+	assert.True(t, errors.Is(err, solanarpc.ErrAccountNotExist))
+
+	server.HandleResult("getBlockTime", nil)
+	_, err = client.BlockTime(ctx, 5)
+	assert.True(t, errors.Is(err, solanarpc.ErrNullResult))
+	assert.True(t, errors.Is(err, solanarpc.ErrTimeStampNotAvailable))
+
+	server.HandleResult("getClusterNodes", nil)
+	_, err = client.ClusterNodes(ctx)
+	assert.True(t, errors.Is(err, solanarpc.ErrNullResult))
+	assert.True(t, errors.Is(err, solanarpc.ErrSpecifiedBlockNotConfirmed))
+
+	// node errors come back as they are
+	server.Handle("getBalance", func(solanarpc.RPCRequest) (interface{}, error) {
+		return nil, &solanarpc.RPCError{Code: solanarpc.ErrCodeNodeUnhealthy, Message: "Node is unhealthy"}
+	})
+	_, _, err = client.Balance(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri")
+	assert.True(t, errors.Is(err, solanarpc.ErrNodeUnhealthy))
+
+	// the raw API is still there
+	resp, err := client.GetBlockCommitment(ctx, 5)
+	assert.NoError(t, err)
+	_, err = solanarpc.ParseBlockCommitmentResponse(resp)
+	assert.NoError(t, err)
+
+	// parsers answering ErrNullResult get the sentinel of the method too
+	server.HandleResult("getBlockCommitment", nil)
+	_, err = client.BlockCommitment(ctx, 5)
+	assert.True(t, errors.Is(err, solanarpc.ErrNullResult))
+	assert.True(t, errors.Is(err, solanarpc.ErrUnknownBlock))
+
+	server.HandleResult("getTokenSupply", nil)
+	_, _, err = client.TokenSupply(ctx, "83astBRguLMdt2h5U1Tpdq5tjFoJ6noeGwaY3mDLVcri", nil)
+	assert.True(t, errors.Is(err, solanarpc.ErrNullResult))
+	assert.True(t, errors.Is(err, solanarpc.ErrAccountNotExist))
+}
@@ -1,7 +1,10 @@
 package solanarpc
 
 import (
+	"bytes"
+	"context"
 	"crypto/rand"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"math/big"
@@ -16,9 +19,51 @@ func RandomID() uint64 {
 	return nBig.Uint64()
 }
 
+// maxDrainSize is how much of an unread body CloseRespBody reads to keep the
+// connection; a longer body is cheaper to drop with its connection
+const maxDrainSize = 4 << 10
+
 // A better way to close http response body for reusing connection efficiency
 // https://github.com/google/go-github/pull/317
 func CloseRespBody(resp *http.Response) {
-	io.Copy(ioutil.Discard, resp.Body)
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxDrainSize))
 	resp.Body.Close()
 }
+
+// unmarshalNumbers decodes raw into v keeping numbers as json.Number, so that
+// a uint64 above 2^53 comes out of a round trip the way it went in
+func unmarshalNumbers(raw []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// contextError returns ctx.Err() when ctx is done so callers can tell a canceled
+// or expired request apart from a transport or RPC failure.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// requestError is contextError for a request bound to ctx, a child of parent
+// carrying the client Timeout. Running out of that timeout is ErrRequestTimeout.
+func requestError(parent, ctx context.Context, err error) error {
+	if parent.Err() == nil && ctx.Err() != nil {
+		return ErrRequestTimeout
+	}
+	return contextError(parent, err)
+}
+
+// isNullJSON reports whether raw is null or missing
+func isNullJSON(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) == 0 || string(trimmed) == "null"
+}
+
+// isEmptyConfig reports whether config encodes as an empty object
+func isEmptyConfig(config interface{}) bool {
+	raw, err := json.Marshal(config)
+	return err == nil && string(raw) == "{}"
+}